@@ -0,0 +1,55 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+)
+
+var (
+	ErrInvalidCACertificate = errors.New("no valid certificates found in CA file")
+	ErrIncompleteClientCert = errors.New("both CertFile and KeyFile are required for client certificates")
+)
+
+// New builds the tls.Config used by the trace and metric exporters.
+// It returns nil when no TLS option is set, so the exporter falls back to the system defaults.
+func New(cfg *config.TraceExporterTLSConfig) (*tls.Config, error) {
+	if cfg.CAFile == "" && cfg.CertFile == "" && cfg.KeyFile == "" && !cfg.InsecureSkipVerify {
+		return nil, nil //nolint:nilnil // nil config means system defaults
+	}
+
+	//nolint:gosec // InsecureSkipVerify is an explicit opt-in for development setups
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CAFile != "" {
+		caPEM, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, ErrInvalidCACertificate
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, ErrIncompleteClientCert
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
@@ -0,0 +1,312 @@
+package grpcobserve
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/logger"
+	logModel "github.com/nash-567/goObserve/pkg/logger/model"
+	metricsModel "github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/tracing"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Semantic convention attribute keys and metric names for RPC spans.
+// See https://opentelemetry.io/docs/specs/semconv/rpc/.
+const (
+	attrRPCSystem          = "rpc.system"
+	attrRPCService         = "rpc.service"
+	attrRPCMethod          = "rpc.method"
+	attrRPCGRPCStatusCode  = "rpc.grpc.status_code"
+	attrServerAddress      = "server.address"
+	attrNetworkPeerAddress = "network.peer.address"
+
+	rpcSystemGRPC = "grpc"
+
+	metricServerDuration = "rpc.server.duration"
+	metricClientDuration = "rpc.client.duration"
+)
+
+// Interceptors instruments gRPC servers and clients. Every RPC gets a span, a duration
+// measurement when a meter is configured, and server handlers get a request logger in their
+// context. The trace context is propagated through the request metadata.
+type Interceptors struct {
+	tracer         model.Tracer
+	log            logModel.Logger
+	propagator     propagation.TextMapPropagator
+	serverDuration metricsModel.Histogram
+	clientDuration metricsModel.Histogram
+}
+
+// Option configures the Interceptors created by New.
+type Option func(*options)
+
+type options struct {
+	meter      metricsModel.Meter
+	propagator propagation.TextMapPropagator
+}
+
+// WithMeter records the rpc.server.duration and rpc.client.duration histograms with meter.
+func WithMeter(meter metricsModel.Meter) Option {
+	return func(o *options) {
+		o.meter = meter
+	}
+}
+
+// WithPropagator sets the propagator used for the request metadata.
+// The W3C trace context and baggage propagators are used by default.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(o *options) {
+		o.propagator = propagator
+	}
+}
+
+// New creates the interceptors. log is the base of the request loggers added to the server handler contexts.
+func New(tracer model.Tracer, log logModel.Logger, opts ...Option) (*Interceptors, error) {
+	o := options{
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	i := &Interceptors{
+		tracer:     tracer,
+		log:        log,
+		propagator: o.propagator,
+	}
+	if o.meter != nil {
+		var err error
+		i.serverDuration, err = o.meter.Histogram(metricServerDuration,
+			metricsModel.WithUnit("ms"),
+			metricsModel.WithDescription("Measures the duration of inbound RPC."))
+		if err != nil {
+			return nil, err
+		}
+		i.clientDuration, err = o.meter.Histogram(metricClientDuration,
+			metricsModel.WithUnit("ms"),
+			metricsModel.WithDescription("Measures the duration of outbound RPC."))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+// UnaryServerInterceptor instruments unary RPCs handled by a server.
+func (i *Interceptors) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, finish := i.startServer(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		finish(err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor instruments streaming RPCs handled by a server.
+// The span covers the whole stream, until the handler returns.
+func (i *Interceptors) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, finish := i.startServer(ss.Context(), info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		finish(err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor instruments unary RPCs made by a client.
+func (i *Interceptors) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		ctx, finish := i.startClient(ctx, method, cc.Target())
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		finish(err)
+		return err
+	}
+}
+
+// StreamClientInterceptor instruments streaming RPCs made by a client.
+// The span ends once the stream is read to the end or fails, or when the stream ends without being
+// received from, after its context is cancelled or when the server failed it before sending a response,
+// so an abandoned stream does not leak its span.
+func (i *Interceptors) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		ctx, finish := i.startClient(ctx, method, cc.Target())
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			finish(err)
+			return nil, err
+		}
+		s := &clientStream{ClientStream: cs, serverStreams: desc.ServerStreams, finish: finish, done: make(chan struct{})}
+		go func() {
+			// gRPC cancels the context of the stream once it is finished, which happens when the caller
+			// context is done, and in Header when the server responded with an error status only
+			_, _ = cs.Header()
+			select {
+			case <-cs.Context().Done():
+				s.end(status.FromContextError(cs.Context().Err()).Err())
+			case <-s.done:
+			}
+		}()
+		return s, nil
+	}
+}
+
+func (i *Interceptors) startServer(ctx context.Context, fullMethod string) (context.Context, func(error)) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = i.propagator.Extract(ctx, metadataCarrier(md))
+
+	service, method := splitFullMethod(fullMethod)
+	attrs := rpcAttributes(service, method)
+	spanAttrs := attrs
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		spanAttrs = append(spanAttrs[:len(spanAttrs):len(spanAttrs)], model.NewKeyValue(attrNetworkPeerAddress, p.Addr.String()))
+	}
+	ctx, span := i.tracer.StartSpan(ctx, spanName(fullMethod),
+		model.WithSpanKind(model.SpanKindServer),
+		model.WithAttributes(spanAttrs...),
+	)
+	ctx = logger.NewContextWithLogger(ctx, i.log.WithFields(logModel.Fields{
+		attrRPCSystem:  rpcSystemGRPC,
+		attrRPCService: service,
+		attrRPCMethod:  method,
+	}))
+
+	start := time.Now()
+	return ctx, func(err error) {
+		code := status.Code(err)
+		span.SetAttributes(model.NewKeyValue(attrRPCGRPCStatusCode, int64(code)))
+		if isServerError(code) {
+			tracing.RecordResult(span, err)
+		}
+		span.End()
+		record(ctx, i.serverDuration, start, attrs, code)
+	}
+}
+
+func (i *Interceptors) startClient(ctx context.Context, fullMethod, target string) (context.Context, func(error)) {
+	service, method := splitFullMethod(fullMethod)
+	attrs := rpcAttributes(service, method)
+	ctx, span := i.tracer.StartSpan(ctx, spanName(fullMethod),
+		model.WithSpanKind(model.SpanKindClient),
+		model.WithAttributes(append(attrs[:len(attrs):len(attrs)], model.NewKeyValue(attrServerAddress, target))...),
+	)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	i.propagator.Inject(ctx, metadataCarrier(md))
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	start := time.Now()
+	return ctx, func(err error) {
+		code := status.Code(err)
+		span.SetAttributes(model.NewKeyValue(attrRPCGRPCStatusCode, int64(code)))
+		tracing.RecordResult(span, err)
+		span.End()
+		record(ctx, i.clientDuration, start, attrs, code)
+	}
+}
+
+func record(ctx context.Context, h metricsModel.Histogram, start time.Time, attrs []model.KeyValue, code codes.Code) {
+	if h == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+	h.Record(ctx, elapsed, append(attrs, model.NewKeyValue(attrRPCGRPCStatusCode, int64(code)))...)
+}
+
+// isServerError reports whether the status code is an error caused by the server,
+// the other codes are caused by the client and do not make the server span fail.
+func isServerError(code codes.Code) bool {
+	switch code { //nolint:exhaustive // the other codes are not server errors
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// splitFullMethod splits "/package.Service/Method" into the service and method names.
+func splitFullMethod(fullMethod string) (string, string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	service, method, ok := strings.Cut(name, "/")
+	if !ok {
+		return "", name
+	}
+	return service, method
+}
+
+func spanName(fullMethod string) string {
+	return strings.TrimPrefix(fullMethod, "/")
+}
+
+func rpcAttributes(service, method string) []model.KeyValue {
+	return []model.KeyValue{
+		model.NewKeyValue(attrRPCSystem, rpcSystemGRPC),
+		model.NewKeyValue(attrRPCService, service),
+		model.NewKeyValue(attrRPCMethod, method),
+	}
+}
+
+// serverStream overrides the context of the stream with the instrumented one.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context //nolint:containedctx // the context of the stream
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// clientStream ends the RPC span when the stream completes.
+type clientStream struct {
+	grpc.ClientStream
+	serverStreams bool
+	finish        func(error)
+	once          sync.Once
+	done          chan struct{}
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.end(nil)
+	case err != nil:
+		s.end(err)
+	case !s.serverStreams:
+		// a single response ends the RPC
+		s.end(nil)
+	}
+	return err
+}
+
+func (s *clientStream) end(err error) {
+	s.once.Do(func() {
+		s.finish(err)
+		close(s.done)
+	})
+}
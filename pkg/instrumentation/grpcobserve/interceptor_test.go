@@ -0,0 +1,232 @@
+package grpcobserve_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otelCodes "go.opentelemetry.io/otel/codes"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/nash-567/goObserve/pkg/instrumentation/grpcobserve"
+	"github.com/nash-567/goObserve/pkg/logger"
+	logConfig "github.com/nash-567/goObserve/pkg/logger/config"
+	metricsConfig "github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/otelmeter"
+	tracingConfig "github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+// failStreamKey is the metadata key making the test server fail a stream.
+const failStreamKey = "x-fail-stream"
+
+type testEnv struct {
+	client   healthpb.HealthClient
+	health   *health.Server
+	exporter *tracetest.InMemoryExporter
+	reader   *sdkMetric.ManualReader
+	logs     *strings.Builder
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	env := &testEnv{
+		health:   health.NewServer(),
+		exporter: tracetest.NewInMemoryExporter(),
+		reader:   sdkMetric.NewManualReader(),
+		logs:     new(strings.Builder),
+	}
+	tracer := oteltracer.NewTracer(&tracingConfig.TracingConfig{},
+		sdkTrace.NewTracerProvider(sdkTrace.WithSyncer(env.exporter)))
+	meter := otelmeter.NewMeter(&metricsConfig.MetricsConfig{}, sdkMetric.NewMeterProvider(sdkMetric.WithReader(env.reader)))
+	log := logger.NewSlogLogger(&logConfig.Config{Output: env.logs, Level: "INFO"})
+
+	interceptors, err := grpcobserve.New(tracer, log, grpcobserve.WithMeter(meter))
+	require.NoError(t, err)
+
+	// logs from the handler context, to check the request logger is attached
+	logFromHandler := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		logger.FromContext(ctx).Info("handling")
+		return handler(ctx, req)
+	}
+
+	// fails the streams asked to, to check the server errors nobody receives
+	failOnRequest := func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if md, _ := metadata.FromIncomingContext(ss.Context()); len(md.Get(failStreamKey)) > 0 {
+			return status.Error(codes.Internal, "failed stream")
+		}
+		return handler(srv, ss)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.UnaryServerInterceptor(), logFromHandler),
+		grpc.ChainStreamInterceptor(interceptors.StreamServerInterceptor(), failOnRequest),
+	)
+	healthpb.RegisterHealthServer(server, env.health)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(interceptors.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(interceptors.StreamClientInterceptor()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	env.client = healthpb.NewHealthClient(conn)
+	return env
+}
+
+func spanByKind(t *testing.T, spans tracetest.SpanStubs, kind trace.SpanKind) tracetest.SpanStub {
+	t.Helper()
+	for _, s := range spans {
+		if s.SpanKind == kind {
+			return s
+		}
+	}
+	t.Fatalf("no %s span", kind)
+	return tracetest.SpanStub{}
+}
+
+func attributes(stub tracetest.SpanStub) map[string]string {
+	attrs := map[string]string{}
+	for _, kv := range stub.Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	return attrs
+}
+
+func TestInterceptors_Unary(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	_, err := env.client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	spans := env.exporter.GetSpans()
+	require.Len(t, spans, 2)
+	server := spanByKind(t, spans, trace.SpanKindServer)
+	client := spanByKind(t, spans, trace.SpanKindClient)
+
+	assert.Equal(t, "grpc.health.v1.Health/Check", server.Name)
+	assert.Equal(t, client.SpanContext.TraceID(), server.SpanContext.TraceID())
+	assert.Equal(t, client.SpanContext.SpanID(), server.Parent.SpanID())
+	assert.True(t, server.Parent.IsRemote())
+	assert.Equal(t, map[string]string{
+		"rpc.system":           "grpc",
+		"rpc.service":          "grpc.health.v1.Health",
+		"rpc.method":           "Check",
+		"rpc.grpc.status_code": "0",
+		"network.peer.address": "bufconn",
+	}, attributes(server))
+	assert.Equal(t, "passthrough:///bufnet", attributes(client)["server.address"])
+
+	assert.Contains(t, env.logs.String(), `"msg":"handling"`)
+	assert.Contains(t, env.logs.String(), `"rpc.method":"Check"`)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, env.reader.Collect(context.Background(), &rm))
+	names := map[string]uint64{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		names[m.Name] = m.Data.(metricdata.Histogram[float64]).DataPoints[0].Count
+	}
+	assert.Equal(t, map[string]uint64{"rpc.server.duration": 1, "rpc.client.duration": 1}, names)
+}
+
+func TestInterceptors_UnaryError(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	_, err := env.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	spans := env.exporter.GetSpans()
+	require.Len(t, spans, 2)
+	// NotFound is caused by the client, it is only an error on the client side
+	assert.Equal(t, otelCodes.Unset, spanByKind(t, spans, trace.SpanKindServer).Status.Code)
+	assert.Equal(t, otelCodes.Error, spanByKind(t, spans, trace.SpanKindClient).Status.Code)
+	assert.Equal(t, "5", attributes(spanByKind(t, spans, trace.SpanKindServer))["rpc.grpc.status_code"])
+}
+
+func TestInterceptors_Stream(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := env.client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	cancel()
+	_, err = stream.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+
+	assert.Eventually(t, func() bool { return len(env.exporter.GetSpans()) == 2 }, time.Second, 10*time.Millisecond)
+	spans := env.exporter.GetSpans()
+	server := spanByKind(t, spans, trace.SpanKindServer)
+	client := spanByKind(t, spans, trace.SpanKindClient)
+	assert.Equal(t, "grpc.health.v1.Health/Watch", server.Name)
+	assert.Equal(t, client.SpanContext.SpanID(), server.Parent.SpanID())
+}
+
+func TestInterceptors_StreamAbandoned(t *testing.T) {
+	t.Parallel()
+	clientSpan := func(env *testEnv) tracetest.SpanStub {
+		assert.Eventually(t, func() bool {
+			for _, s := range env.exporter.GetSpans() {
+				if s.SpanKind == trace.SpanKindClient {
+					return true
+				}
+			}
+			return false
+		}, time.Second, 10*time.Millisecond)
+		return spanByKind(t, env.exporter.GetSpans(), trace.SpanKindClient)
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		_, err := env.client.Watch(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		// the stream is never received from, cancelling its context must still end the client span
+		cancel()
+
+		assert.Equal(t, "1", attributes(clientSpan(env))["rpc.grpc.status_code"])
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		t.Parallel()
+		env := newTestEnv(t)
+		// the context is never cancelled, the stream ends with the server error nobody receives
+		ctx := metadata.AppendToOutgoingContext(context.Background(), failStreamKey, "true")
+		stream, err := env.client.Watch(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+
+		span := clientSpan(env)
+		assert.NotEqual(t, "0", attributes(span)["rpc.grpc.status_code"])
+		assert.Equal(t, otelCodes.Error, span.Status.Code)
+		// the status stays available to a late receiver
+		_, err = stream.Recv()
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
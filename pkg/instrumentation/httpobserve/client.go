@@ -0,0 +1,71 @@
+package httpobserve
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/tracing"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Transport instruments the requests sent through base, http.DefaultTransport when nil.
+// The responses with a 4xx or 5xx status code make the span fail. The span ends when the
+// response headers are received, the time to read the body is not included.
+//
+//nolint:ireturn // wraps any round tripper
+func (i *Instrumentation) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, instr: i}
+}
+
+type transport struct {
+	base  http.RoundTripper
+	instr *Instrumentation
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	m := method(r)
+	attrs := []model.KeyValue{
+		model.NewKeyValue(attrHTTPRequestMethod, m),
+		model.NewKeyValue(attrURLScheme, scheme(r)),
+		model.NewKeyValue(attrServerAddress, t.instr.hosts.get(r.URL.Hostname())),
+	}
+	ctx, span := t.instr.tracer.StartSpan(r.Context(), m,
+		model.WithSpanKind(model.SpanKindClient),
+		model.WithAttributes(append(attrs[:len(attrs):len(attrs)], model.NewKeyValue(attrURLFull, redactedURL(r)))...),
+	)
+
+	// the request must not be modified by a round tripper
+	r = r.Clone(ctx)
+	t.instr.propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(r)
+	switch {
+	case err != nil:
+		attrs = append(attrs, errorTypeAttribute(err, 0))
+		tracing.RecordResult(span, err)
+	case resp.StatusCode >= http.StatusBadRequest:
+		attrs = append(attrs, statusAttribute(resp.StatusCode), errorTypeAttribute(nil, resp.StatusCode))
+		span.SetStatus(model.StatusCodeError, http.StatusText(resp.StatusCode))
+	default:
+		attrs = append(attrs, statusAttribute(resp.StatusCode))
+	}
+	span.SetAttributes(attrs...)
+	span.End()
+	record(ctx, t.instr.clientDuration, start, attrs)
+	return resp, err
+}
+
+// redactedURL returns the URL of the request without its user information.
+func redactedURL(r *http.Request) string {
+	if r.URL.User == nil {
+		return r.URL.String()
+	}
+	u := *r.URL
+	u.User = nil
+	return u.String()
+}
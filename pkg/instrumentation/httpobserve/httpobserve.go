@@ -0,0 +1,217 @@
+package httpobserve
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	logModel "github.com/nash-567/goObserve/pkg/logger/model"
+	metricsModel "github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Semantic convention attribute keys and metric names for HTTP spans.
+// See https://opentelemetry.io/docs/specs/semconv/http/.
+const (
+	attrHTTPRequestMethod      = "http.request.method"
+	attrHTTPRoute              = "http.route"
+	attrHTTPResponseStatusCode = "http.response.status_code"
+	attrErrorType              = "error.type"
+	attrServerAddress          = "server.address"
+	attrURLScheme              = "url.scheme"
+	attrURLPath                = "url.path"
+	attrURLFull                = "url.full"
+	attrNetworkPeerAddress     = "network.peer.address"
+
+	metricServerRequestDuration = "http.server.request.duration"
+	metricClientRequestDuration = "http.client.request.duration"
+
+	// otherValue replaces the methods not in the semantic conventions and the routes and hosts
+	// over the cardinality limit.
+	otherValue = "_OTHER"
+
+	defaultMaxRoutes = 100
+)
+
+// durationBucketBoundaries are the boundaries advised by the semantic conventions, in seconds.
+//
+//nolint:gochecknoglobals // lookup table
+var durationBucketBoundaries = []float64{
+	0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10,
+}
+
+//nolint:gochecknoglobals // lookup table
+var knownMethods = map[string]struct{}{
+	http.MethodConnect: {}, http.MethodDelete: {}, http.MethodGet: {}, http.MethodHead: {},
+	http.MethodOptions: {}, http.MethodPatch: {}, http.MethodPost: {}, http.MethodPut: {}, http.MethodTrace: {},
+}
+
+// Instrumentation instruments HTTP servers and clients. Every request gets a span and, when
+// a meter is configured, a duration measurement, from which the request and error rates are
+// derived: the failed requests have the error.type attribute. Server handlers get a request
+// logger in their context and the trace context is propagated through the request headers.
+type Instrumentation struct {
+	tracer         model.Tracer
+	log            logModel.Logger
+	propagator     propagation.TextMapPropagator
+	routeFunc      func(*http.Request) string
+	routes         *boundedValues
+	hosts          *boundedValues
+	serverDuration metricsModel.Histogram
+	clientDuration metricsModel.Histogram
+}
+
+// Option configures the Instrumentation created by New.
+type Option func(*options)
+
+type options struct {
+	meter      metricsModel.Meter
+	propagator propagation.TextMapPropagator
+	routeFunc  func(*http.Request) string
+	maxRoutes  int
+}
+
+// WithMeter records the http.server.request.duration and http.client.request.duration histograms with meter.
+func WithMeter(meter metricsModel.Meter) Option {
+	return func(o *options) {
+		o.meter = meter
+	}
+}
+
+// WithPropagator sets the propagator used for the request headers.
+// The W3C trace context and baggage propagators are used by default.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(o *options) {
+		o.propagator = propagator
+	}
+}
+
+// WithRouteFunc sets the function returning the route template of a server request, e.g. "/users/{id}",
+// usually taken from the router. It is called once the handler returns, so the routers matching the
+// request in the handler chain have set it. The routes set with Route or SetRoute are used otherwise.
+func WithRouteFunc(fn func(*http.Request) string) Option {
+	return func(o *options) {
+		o.routeFunc = fn
+	}
+}
+
+// WithMaxRoutes bounds the number of distinct http.route values of the server and server.address
+// values of the client, 100 by default. The values seen after the limit is reached are replaced
+// with "_OTHER", so a raw path used as route cannot blow the cardinality of the metrics up.
+func WithMaxRoutes(limit int) Option {
+	return func(o *options) {
+		o.maxRoutes = limit
+	}
+}
+
+// New creates the instrumentation. log is the base of the request loggers added to the server handler contexts.
+func New(tracer model.Tracer, log logModel.Logger, opts ...Option) (*Instrumentation, error) {
+	o := options{
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+		maxRoutes:  defaultMaxRoutes,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	i := &Instrumentation{
+		tracer:     tracer,
+		log:        log,
+		propagator: o.propagator,
+		routeFunc:  o.routeFunc,
+		routes:     newBoundedValues(o.maxRoutes),
+		hosts:      newBoundedValues(o.maxRoutes),
+	}
+	if o.meter != nil {
+		var err error
+		i.serverDuration, err = o.meter.Histogram(metricServerRequestDuration,
+			metricsModel.WithUnit("s"),
+			metricsModel.WithDescription("Duration of HTTP server requests."),
+			metricsModel.WithBucketBoundaries(durationBucketBoundaries...))
+		if err != nil {
+			return nil, err
+		}
+		i.clientDuration, err = o.meter.Histogram(metricClientRequestDuration,
+			metricsModel.WithUnit("s"),
+			metricsModel.WithDescription("Duration of HTTP client requests."),
+			metricsModel.WithBucketBoundaries(durationBucketBoundaries...))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+func record(ctx context.Context, h metricsModel.Histogram, start time.Time, attrs []model.KeyValue) {
+	if h == nil {
+		return
+	}
+	h.Record(ctx, time.Since(start).Seconds(), attrs...)
+}
+
+// method returns the method of the request, "_OTHER" for the methods not in the semantic conventions.
+func method(r *http.Request) string {
+	if r.Method == "" {
+		return http.MethodGet
+	}
+	if _, ok := knownMethods[r.Method]; !ok {
+		return otherValue
+	}
+	return r.Method
+}
+
+func scheme(r *http.Request) string {
+	if r.URL != nil && r.URL.Scheme != "" {
+		return r.URL.Scheme
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func statusAttribute(status int) model.KeyValue {
+	return model.NewKeyValue(attrHTTPResponseStatusCode, status)
+}
+
+func errorTypeAttribute(err error, status int) model.KeyValue {
+	if err != nil {
+		return model.NewKeyValue(attrErrorType, fmt.Sprintf("%T", err))
+	}
+	return model.NewKeyValue(attrErrorType, strconv.Itoa(status))
+}
+
+// boundedValues accepts up to limit distinct values, the values seen after are replaced with "_OTHER".
+type boundedValues struct {
+	mu     sync.RWMutex
+	limit  int
+	values map[string]struct{}
+}
+
+func newBoundedValues(limit int) *boundedValues {
+	return &boundedValues{limit: limit, values: map[string]struct{}{}}
+}
+
+func (b *boundedValues) get(value string) string {
+	b.mu.RLock()
+	_, ok := b.values[value]
+	b.mu.RUnlock()
+	if ok {
+		return value
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok = b.values[value]; ok {
+		return value
+	}
+	if len(b.values) >= b.limit {
+		return otherValue
+	}
+	b.values[value] = struct{}{}
+	return value
+}
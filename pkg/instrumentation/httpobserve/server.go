@@ -0,0 +1,182 @@
+package httpobserve
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/logger"
+	logModel "github.com/nash-567/goObserve/pkg/logger/model"
+	"github.com/nash-567/goObserve/pkg/tracing"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+type routeKey struct{}
+
+// routeHolder is added to the request context by Handler, so the route matched deeper in the
+// handler chain is known once the handler returns. It is guarded by a mutex, as the handlers may
+// set the route from another goroutine than the one serving the request.
+type routeHolder struct {
+	mu    sync.Mutex
+	route string
+}
+
+func (h *routeHolder) set(route string) {
+	h.mu.Lock()
+	h.route = route
+	h.mu.Unlock()
+}
+
+func (h *routeHolder) get() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.route
+}
+
+// SetRoute sets the route template of the server request handled with ctx, e.g. "/users/{id}".
+// It does nothing when the request is not handled by Instrumentation.Handler. It is safe for
+// concurrent use, the last route set before the handler returns is used.
+func SetRoute(ctx context.Context, route string) {
+	if holder, ok := ctx.Value(routeKey{}).(*routeHolder); ok {
+		holder.set(route)
+	}
+}
+
+// Route wraps the handler registered for a route template, setting the route of its requests:
+//
+//	mux.Handle("GET /users/{id}", httpobserve.Route("/users/{id}", usersHandler))
+func Route(route string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SetRoute(r.Context(), route)
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Handler instruments the requests handled by next. The server span is named after the method,
+// then "{method} {route}" once the handler returns when the route is known. The responses with a
+// 5xx status code make the span fail. A panic of next is recorded as a 500 response and continues.
+func (i *Instrumentation) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := i.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		m := method(r)
+		ctx, span := i.tracer.StartSpan(ctx, m,
+			model.WithSpanKind(model.SpanKindServer),
+			model.WithAttributes(
+				model.NewKeyValue(attrHTTPRequestMethod, m),
+				model.NewKeyValue(attrURLScheme, scheme(r)),
+				model.NewKeyValue(attrURLPath, r.URL.Path),
+				model.NewKeyValue(attrNetworkPeerAddress, r.RemoteAddr),
+			),
+		)
+		holder := &routeHolder{}
+		ctx = context.WithValue(ctx, routeKey{}, holder)
+		ctx = logger.NewContextWithLogger(ctx, i.log.WithFields(logModel.Fields{
+			attrHTTPRequestMethod: m,
+			attrURLPath:           r.URL.Path,
+		}))
+		r = r.WithContext(ctx)
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+		start := time.Now()
+		defer func() {
+			if rec := recover(); rec != nil {
+				tracing.RecordPanic(span, rec)
+				i.finishServer(ctx, r, span, holder, http.StatusInternalServerError, start)
+				panic(rec)
+			}
+			i.finishServer(ctx, r, span, holder, rw.status, start)
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func (i *Instrumentation) finishServer(
+	ctx context.Context,
+	r *http.Request,
+	span model.Span,
+	holder *routeHolder,
+	status int,
+	start time.Time,
+) {
+	attrs := []model.KeyValue{
+		model.NewKeyValue(attrHTTPRequestMethod, method(r)),
+		model.NewKeyValue(attrURLScheme, scheme(r)),
+		statusAttribute(status),
+	}
+	route := holder.get()
+	if i.routeFunc != nil {
+		route = i.routeFunc(r)
+	}
+	if route != "" {
+		route = i.routes.get(route)
+		attrs = append(attrs, model.NewKeyValue(attrHTTPRoute, route))
+		span.SetName(method(r) + " " + route)
+	}
+	if status >= http.StatusInternalServerError {
+		attrs = append(attrs, errorTypeAttribute(nil, status))
+		span.SetStatus(model.StatusCodeError, http.StatusText(status))
+	}
+	span.SetAttributes(attrs...)
+	span.End()
+	record(ctx, i.serverDuration, start, attrs)
+}
+
+// responseWriter keeps the status code of the response.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher for the streaming handlers.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker for the handlers taking over the connection, e.g. for WebSockets.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %T is not a http.Hijacker", http.ErrNotSupported, w.ResponseWriter)
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Push implements http.Pusher for the HTTP/2 server pushes.
+func (w *responseWriter) Push(target string, opts *http.PushOptions) error {
+	p, ok := w.ResponseWriter.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+	return p.Push(target, opts)
+}
+
+// Unwrap gives http.ResponseController access to the other optional interfaces of the writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,336 @@
+package sqlobserve
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+)
+
+var (
+	ErrNamedParametersUnsupported = errors.New("sql: driver does not support the use of Named Parameters")
+	ErrTxOptionsUnsupported       = errors.New("sql: driver does not support non-default transaction options")
+)
+
+// The wrappers implement all the optional driver interfaces. When the wrapped value does not
+// implement one, they fall back to what database/sql would do, most of the time by returning
+// driver.ErrSkip.
+var (
+	_ driver.DriverContext      = (*wrappedDriver)(nil)
+	_ driver.ConnBeginTx        = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+	_ driver.SessionResetter    = (*conn)(nil)
+	_ driver.Validator          = (*conn)(nil)
+	_ driver.NamedValueChecker  = (*conn)(nil)
+	_ driver.StmtExecContext    = (*stmt)(nil)
+	_ driver.StmtQueryContext   = (*stmt)(nil)
+	_ driver.NamedValueChecker  = (*stmt)(nil)
+)
+
+type wrappedDriver struct {
+	driver driver.Driver
+	instr  *instrumenter
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{conn: c, instr: d.instr}, nil
+}
+
+func (d *wrappedDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.driver.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &connector{connector: c, driver: d}, nil
+	}
+	return &connector{connector: dsnConnector{name: name, driver: d.driver}, driver: d}, nil
+}
+
+type connector struct {
+	connector driver.Connector
+	driver    *wrappedDriver
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	cn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{conn: cn, instr: c.driver.instr}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// dsnConnector is the connector of the drivers not implementing driver.DriverContext.
+type dsnConnector struct {
+	name   string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type conn struct {
+	conn  driver.Conn
+	instr *instrumenter
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	ctx, end := c.instr.start(ctx, "Prepare", query)
+	var (
+		s   driver.Stmt
+		err error
+	)
+	if cpc, ok := c.conn.(driver.ConnPrepareContext); ok {
+		s, err = cpc.PrepareContext(ctx, query)
+	} else {
+		s, err = c.conn.Prepare(query)
+	}
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{stmt: s, query: query, instr: c.instr}, nil
+}
+
+func (c *conn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	spanCtx, end := c.instr.start(ctx, "Begin", "")
+	var (
+		dtx driver.Tx
+		err error
+	)
+	if cbt, ok := c.conn.(driver.ConnBeginTx); ok {
+		dtx, err = cbt.BeginTx(spanCtx, opts)
+	} else if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		err = ErrTxOptionsUnsupported
+	} else {
+		dtx, err = c.conn.Begin() //nolint:staticcheck // fallback for drivers without ConnBeginTx
+	}
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+	return &tx{tx: dtx, ctx: ctx, instr: c.instr}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, isExecerContext := c.conn.(driver.ExecerContext)
+	legacyExecer, isExecer := c.conn.(driver.Execer) //nolint:staticcheck // fallback for old drivers
+	if !isExecerContext && !isExecer {
+		// database/sql prepares a statement instead, which is instrumented on its own
+		return nil, driver.ErrSkip
+	}
+
+	end := c.instr.startSkippable(ctx, "Exec", query)
+	var (
+		res driver.Result
+		err error
+	)
+	if isExecerContext {
+		res, err = execer.ExecContext(ctx, query, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			res, err = legacyExecer.Exec(query, values)
+		}
+	}
+	end(err, resultAttributes(res, err)...)
+	return res, err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, isQueryerContext := c.conn.(driver.QueryerContext)
+	legacyQueryer, isQueryer := c.conn.(driver.Queryer) //nolint:staticcheck // fallback for old drivers
+	if !isQueryerContext && !isQueryer {
+		// database/sql prepares a statement instead, which is instrumented on its own
+		return nil, driver.ErrSkip
+	}
+
+	end := c.instr.startSkippable(ctx, "Query", query)
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if isQueryerContext {
+		rows, err = queryer.QueryContext(ctx, query, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = legacyQueryer.Query(query, values)
+		}
+	}
+	end(err)
+	return rows, err
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type stmt struct {
+	stmt  driver.Stmt
+	query string
+	instr *instrumenter
+}
+
+func (s *stmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *stmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), valuesToNamedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), valuesToNamedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, end := s.instr.start(ctx, "Exec", s.query)
+	var (
+		res driver.Result
+		err error
+	)
+	if sec, ok := s.stmt.(driver.StmtExecContext); ok {
+		res, err = sec.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			res, err = s.stmt.Exec(values) //nolint:staticcheck // fallback for old drivers
+		}
+	}
+	end(err, resultAttributes(res, err)...)
+	return res, err
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, end := s.instr.start(ctx, "Query", s.query)
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if sqc, ok := s.stmt.(driver.StmtQueryContext); ok {
+		rows, err = sqc.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = s.stmt.Query(values) //nolint:staticcheck // fallback for old drivers
+		}
+	}
+	end(err)
+	return rows, err
+}
+
+func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type tx struct {
+	tx    driver.Tx
+	ctx   context.Context //nolint:containedctx // the context the transaction was started with
+	instr *instrumenter
+}
+
+func (t *tx) Commit() error {
+	_, end := t.instr.start(t.ctx, "Commit", "")
+	err := t.tx.Commit()
+	end(err)
+	return err
+}
+
+func (t *tx) Rollback() error {
+	_, end := t.instr.start(t.ctx, "Rollback", "")
+	err := t.tx.Rollback()
+	end(err)
+	return err
+}
+
+func resultAttributes(res driver.Result, err error) []model.KeyValue {
+	if err != nil || res == nil {
+		return nil
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil
+	}
+	return []model.KeyValue{model.NewKeyValue(attrDBRowsAffected, rows)}
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, ErrNamedParametersUnsupported
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+func valuesToNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
@@ -0,0 +1,176 @@
+package sqlobserve
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/logger"
+	logModel "github.com/nash-567/goObserve/pkg/logger/model"
+	"github.com/nash-567/goObserve/pkg/tracing"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+)
+
+// Semantic convention attribute keys for database client spans.
+// See https://opentelemetry.io/docs/specs/semconv/database/.
+const (
+	attrDBSystem       = "db.system"
+	attrDBStatement    = "db.statement"
+	attrDBOperation    = "db.operation"
+	attrDBRowsAffected = "db.rows_affected"
+)
+
+// Option configures the instrumentation.
+type Option func(*options)
+
+type options struct {
+	dbSystem           string
+	sanitize           bool
+	slowQueryThreshold time.Duration
+}
+
+// WithDBSystem sets the db.system attribute of the spans, e.g. "postgresql" or "mysql".
+func WithDBSystem(system string) Option {
+	return func(o *options) {
+		o.dbSystem = system
+	}
+}
+
+// WithSanitizedStatements replaces the string and number literals of the db.statement
+// attribute and of the slow query logs with "?", so values do not leak into the telemetry.
+func WithSanitizedStatements() Option {
+	return func(o *options) {
+		o.sanitize = true
+	}
+}
+
+// WithSlowQueryThreshold logs the statements taking at least threshold at WARN level,
+// through the logger of the query context.
+func WithSlowQueryThreshold(threshold time.Duration) Option {
+	return func(o *options) {
+		o.slowQueryThreshold = threshold
+	}
+}
+
+// Open opens a database like sql.Open, through the instrumented version of the driver registered as driverName.
+func Open(driverName, dataSourceName string, tracer model.Tracer, opts ...Option) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	d := db.Driver()
+	if err := db.Close(); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	wrapped := &wrappedDriver{driver: d, instr: newInstrumenter(tracer, opts)}
+	connector, err := wrapped.OpenConnector(dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// Wrap returns an instrumented version of d, e.g. to register it with sql.Register.
+//
+//nolint:ireturn // the wrapper implements the same optional interfaces as the driver package expects
+func Wrap(d driver.Driver, tracer model.Tracer, opts ...Option) driver.Driver {
+	return &wrappedDriver{driver: d, instr: newInstrumenter(tracer, opts)}
+}
+
+// instrumenter creates the spans and slow query logs of the wrapped connections.
+type instrumenter struct {
+	tracer model.Tracer
+	options
+}
+
+func newInstrumenter(tracer model.Tracer, opts []Option) *instrumenter {
+	i := &instrumenter{tracer: tracer}
+	for _, opt := range opts {
+		opt(&i.options)
+	}
+	return i
+}
+
+// start starts a client span for operation. query is empty for operations without a statement.
+// The returned function ends the span, recording err and logging the query if it was slow.
+func (i *instrumenter) start(ctx context.Context, operation, query string) (context.Context, func(err error, attrs ...model.KeyValue)) {
+	query = i.statement(query)
+	ctx, span := i.tracer.StartSpan(ctx, "sql."+operation, i.spanOptions(operation, query)...)
+	start := time.Now()
+	return ctx, func(err error, attrs ...model.KeyValue) {
+		i.end(ctx, span, operation, query, start, err, attrs...)
+	}
+}
+
+// startSkippable is start for the operations the driver can refuse with driver.ErrSkip, which
+// database/sql then runs another way, e.g. by preparing a statement. The span is only created when
+// the returned function is called with another error, so the driver is called without it in its context.
+func (i *instrumenter) startSkippable(ctx context.Context, operation, query string) func(err error, attrs ...model.KeyValue) {
+	query = i.statement(query)
+	start := time.Now()
+	return func(err error, attrs ...model.KeyValue) {
+		if err == driver.ErrSkip { //nolint:errorlint // ErrSkip is returned as is by the drivers
+			return
+		}
+		spanCtx, span := i.tracer.StartSpan(ctx, "sql."+operation,
+			append(i.spanOptions(operation, query), model.WithTimestamp(start))...)
+		i.end(spanCtx, span, operation, query, start, err, attrs...)
+	}
+}
+
+func (i *instrumenter) spanOptions(operation, query string) []model.SpanStartOption {
+	attrs := []model.KeyValue{model.NewKeyValue(attrDBOperation, operation)}
+	if i.dbSystem != "" {
+		attrs = append(attrs, model.NewKeyValue(attrDBSystem, i.dbSystem))
+	}
+	if query != "" {
+		attrs = append(attrs, model.NewKeyValue(attrDBStatement, query))
+	}
+	return []model.SpanStartOption{
+		model.WithSpanKind(model.SpanKindClient),
+		model.WithAttributes(attrs...),
+	}
+}
+
+func (i *instrumenter) end(
+	ctx context.Context,
+	span model.Span,
+	operation, query string,
+	start time.Time,
+	err error,
+	attrs ...model.KeyValue,
+) {
+	if len(attrs) > 0 {
+		span.SetAttributes(attrs...)
+	}
+	tracing.RecordResult(span, err)
+	span.End()
+
+	elapsed := time.Since(start)
+	if query != "" && i.slowQueryThreshold > 0 && elapsed >= i.slowQueryThreshold {
+		logger.FromContext(ctx).WithFields(logModel.Fields{
+			attrDBOperation: operation,
+			attrDBStatement: query,
+			"duration_ms":   elapsed.Milliseconds(),
+		}).Warn("slow query")
+	}
+}
+
+// numberLiteral matches the numbers with the character before them, which must not make them part
+// of an identifier or of a placeholder like $1, :1 or @p1. RE2 has no lookbehind, so the character
+// is kept by the replacement.
+var (
+	stringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numberLiteral = regexp.MustCompile(`(^|[^\w$:@])\d+(?:\.\d+)?\b`)
+)
+
+func (i *instrumenter) statement(query string) string {
+	if !i.sanitize {
+		return query
+	}
+	return numberLiteral.ReplaceAllString(stringLiteral.ReplaceAllString(query, "?"), "${1}?")
+}
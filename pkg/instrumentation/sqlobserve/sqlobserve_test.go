@@ -0,0 +1,329 @@
+package sqlobserve_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otelCodes "go.opentelemetry.io/otel/codes"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nash-567/goObserve/pkg/instrumentation/sqlobserve"
+	"github.com/nash-567/goObserve/pkg/logger"
+	logConfig "github.com/nash-567/goObserve/pkg/logger/config"
+	metricsConfig "github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/otelmeter"
+	tracingConfig "github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+const fakeDriverName = "sqlobserve-fake"
+
+var errFakeQuery = errors.New("fake: syntax error")
+
+func init() { //nolint:gochecknoinits // drivers can only be registered once
+	sql.Register(fakeDriverName, fakeDriver{})
+}
+
+// fakeDriver is an in-process driver: statements containing FAIL fail, the others
+// affect 3 rows and queries return a single row. The connection refuses to run the
+// statements containing SKIP with driver.ErrSkip, so database/sql prepares them.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{query: query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, "SKIP") {
+		return nil, driver.ErrSkip
+	}
+	return runExec(query)
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if strings.Contains(query, "SKIP") {
+		return nil, driver.ErrSkip
+	}
+	return runQuery(query)
+}
+
+type fakeStmt struct {
+	query string
+}
+
+func (s *fakeStmt) Close() error                               { return nil }
+func (s *fakeStmt) NumInput() int                              { return -1 }
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) { return runExec(s.query) }
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return runQuery(s.query) }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	done bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func runExec(query string) (driver.Result, error) {
+	if strings.Contains(query, "FAIL") {
+		return nil, errFakeQuery
+	}
+	return driver.RowsAffected(3), nil
+}
+
+func runQuery(query string) (driver.Rows, error) {
+	if strings.Contains(query, "FAIL") {
+		return nil, errFakeQuery
+	}
+	return &fakeRows{}, nil
+}
+
+func openTestDB(t *testing.T, opts ...sqlobserve.Option) (*sql.DB, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := oteltracer.NewTracer(&tracingConfig.TracingConfig{},
+		sdkTrace.NewTracerProvider(sdkTrace.WithSyncer(exporter)))
+
+	db, err := sqlobserve.Open(fakeDriverName, "dsn", tracer, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, exporter
+}
+
+func attributes(stub tracetest.SpanStub) map[string]string {
+	attrs := map[string]string{}
+	for _, kv := range stub.Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	return attrs
+}
+
+func spanNames(spans tracetest.SpanStubs) []string {
+	names := make([]string, len(spans))
+	for i, s := range spans {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func TestOpen_Exec(t *testing.T) {
+	t.Parallel()
+	db, exporter := openTestDB(t, sqlobserve.WithDBSystem("fakedb"))
+
+	_, err := db.ExecContext(context.Background(), "DELETE FROM users WHERE name = 'bob'")
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "sql.Exec", spans[0].Name)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+	assert.Equal(t, map[string]string{
+		"db.system":        "fakedb",
+		"db.operation":     "Exec",
+		"db.statement":     "DELETE FROM users WHERE name = 'bob'",
+		"db.rows_affected": "3",
+	}, attributes(spans[0]))
+}
+
+func TestOpen_Query(t *testing.T) {
+	t.Parallel()
+	db, exporter := openTestDB(t)
+
+	var id int
+	require.NoError(t, db.QueryRowContext(context.Background(), "SELECT id FROM users").Scan(&id))
+	assert.Equal(t, 1, id)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "sql.Query", spans[0].Name)
+	assert.Equal(t, "SELECT id FROM users", attributes(spans[0])["db.statement"])
+}
+
+func TestOpen_Error(t *testing.T) {
+	t.Parallel()
+	db, exporter := openTestDB(t)
+
+	_, err := db.ExecContext(context.Background(), "FAIL")
+	require.ErrorIs(t, err, errFakeQuery)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, otelCodes.Error, spans[0].Status.Code)
+	assert.NotContains(t, attributes(spans[0]), "db.rows_affected")
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "exception", spans[0].Events[0].Name)
+}
+
+func TestOpen_PreparedStatement(t *testing.T) {
+	t.Parallel()
+	db, exporter := openTestDB(t)
+
+	stmt, err := db.PrepareContext(context.Background(), "UPDATE users SET name = ?")
+	require.NoError(t, err)
+	defer stmt.Close()
+	_, err = stmt.ExecContext(context.Background(), "alice")
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, []string{"sql.Prepare", "sql.Exec"}, spanNames(spans))
+	assert.Equal(t, "3", attributes(spans[1])["db.rows_affected"])
+}
+
+func TestOpen_Transaction(t *testing.T) {
+	t.Parallel()
+	db, exporter := openTestDB(t)
+
+	ctx, parent := sdkTrace.NewTracerProvider().Tracer("test").Start(context.Background(), "parent")
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "INSERT INTO users VALUES (1)")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	parent.End()
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, []string{"sql.Begin", "sql.Exec", "sql.Commit"}, spanNames(spans))
+	for _, s := range spans {
+		assert.Equal(t, parent.SpanContext().SpanID(), s.Parent.SpanID(), s.Name)
+	}
+}
+
+func TestWithSanitizedStatements(t *testing.T) {
+	t.Parallel()
+	db, exporter := openTestDB(t, sqlobserve.WithSanitizedStatements())
+
+	_, err := db.ExecContext(context.Background(), "UPDATE users SET name = 'o''brien', age = 42 WHERE id = 7")
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "UPDATE users SET name = ?, age = ? WHERE id = ?", attributes(spans[0])["db.statement"])
+}
+
+func TestWithSanitizedStatements_Placeholders(t *testing.T) {
+	t.Parallel()
+	db, exporter := openTestDB(t, sqlobserve.WithSanitizedStatements())
+
+	_, err := db.ExecContext(context.Background(), "DELETE FROM t2 WHERE id = $1 AND n = 5 AND k = :k1 AND p = @p2", 1)
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "DELETE FROM t2 WHERE id = $1 AND n = ? AND k = :k1 AND p = @p2", attributes(spans[0])["db.statement"])
+}
+
+func TestOpen_ErrSkip(t *testing.T) {
+	t.Parallel()
+	db, exporter := openTestDB(t, sqlobserve.WithSlowQueryThreshold(time.Nanosecond))
+
+	logs := new(strings.Builder)
+	ctx := logger.NewContextWithLogger(context.Background(),
+		logger.NewSlogLogger(&logConfig.Config{Output: logs, Level: "INFO"}))
+	_, err := db.ExecContext(ctx, "DELETE FROM users -- SKIP")
+	require.NoError(t, err)
+	rows, err := db.QueryContext(ctx, "SELECT id FROM users -- SKIP")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+
+	// the refused statements are only traced once, as prepared statements
+	spans := exporter.GetSpans()
+	assert.Equal(t, []string{"sql.Prepare", "sql.Exec", "sql.Prepare", "sql.Query"}, spanNames(spans))
+	for _, s := range spans {
+		assert.Equal(t, otelCodes.Unset, s.Status.Code, s.Name)
+	}
+	assert.Equal(t, 4, strings.Count(logs.String(), "slow query"))
+}
+
+func TestWithSlowQueryThreshold(t *testing.T) {
+	t.Parallel()
+	db, _ := openTestDB(t, sqlobserve.WithSlowQueryThreshold(time.Nanosecond))
+
+	logs := new(strings.Builder)
+	ctx := logger.NewContextWithLogger(context.Background(),
+		logger.NewSlogLogger(&logConfig.Config{Output: logs, Level: "INFO"}))
+	_, err := db.ExecContext(ctx, "DELETE FROM users")
+	require.NoError(t, err)
+
+	assert.Contains(t, logs.String(), "slow query")
+	assert.Contains(t, logs.String(), "DELETE FROM users")
+	assert.Contains(t, logs.String(), "WARN")
+}
+
+func TestReportPoolStats(t *testing.T) {
+	t.Parallel()
+	db, _ := openTestDB(t)
+	db.SetMaxOpenConns(5)
+	require.NoError(t, db.Ping())
+
+	reader := sdkMetric.NewManualReader()
+	meter := otelmeter.NewMeter(&metricsConfig.MetricsConfig{}, sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)))
+
+	registrations, err := sqlobserve.ReportPoolStats(db, meter, "main")
+	require.NoError(t, err)
+
+	collect := func() map[string]float64 {
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		got := map[string]float64{}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				data, ok := m.Data.(metricdata.Sum[float64])
+				require.True(t, ok, m.Name)
+				// the connections of the pool go up and down, the other statistics are cumulative
+				assert.Equal(t, !strings.HasPrefix(m.Name, "db.client.connections."), data.IsMonotonic, m.Name)
+				for _, dp := range data.DataPoints {
+					name := m.Name
+					if state, ok := dp.Attributes.Value("state"); ok {
+						name += "." + state.AsString()
+					}
+					pool, _ := dp.Attributes.Value("pool.name")
+					assert.Equal(t, "main", pool.AsString())
+					got[name] = dp.Value
+				}
+			}
+		}
+		return got
+	}
+	assert.Equal(t, map[string]float64{
+		"db.client.connections.usage.idle":          1,
+		"db.client.connections.usage.used":          0,
+		"db.client.connections.max":                 5,
+		"goobserve.db.client.connections.waits":     0,
+		"goobserve.db.client.connections.wait_time": 0,
+		"goobserve.db.client.connections.closed":    0,
+	}, collect())
+
+	for _, r := range registrations {
+		require.NoError(t, r.Unregister())
+	}
+	assert.Empty(t, collect())
+}
@@ -0,0 +1,94 @@
+package sqlobserve
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	metricsModel "github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+)
+
+// Semantic convention metric names of the connection pools.
+// See https://opentelemetry.io/docs/specs/semconv/database/database-metrics/.
+const (
+	metricConnectionsUsage = "db.client.connections.usage"
+	metricConnectionsMax   = "db.client.connections.max"
+
+	attrPoolName = "pool.name"
+	attrState    = "state"
+
+	stateIdle = "idle"
+	stateUsed = "used"
+)
+
+// Metric names of the cumulative pool statistics, which have no semantic convention.
+const (
+	metricConnectionsWaits    = "goobserve.db.client.connections.waits"
+	metricConnectionsWaitTime = "goobserve.db.client.connections.wait_time"
+	metricConnectionsClosed   = "goobserve.db.client.connections.closed"
+)
+
+// registerPoolInstruments registers the observable instruments of the statistics of db, read at
+// every collection: up-down counters for the connections of the pool and counters for the
+// cumulative statistics.
+func registerPoolInstruments(db *sql.DB, meter metricsModel.Meter, pool model.KeyValue) ([]metricsModel.Registration, error) {
+	type observe func(o metricsModel.Observer, stats sql.DBStats)
+	observeValue := func(value func(sql.DBStats) float64) observe {
+		return func(o metricsModel.Observer, stats sql.DBStats) {
+			o.Observe(value(stats), pool)
+		}
+	}
+	instruments := []struct {
+		register                func(string, metricsModel.Callback, ...metricsModel.InstrumentOption) (metricsModel.Registration, error)
+		name, unit, description string
+		observe                 observe
+	}{
+		{meter.ObservableUpDownCounter, metricConnectionsUsage, "{connection}",
+			"The number of connections that are currently in state described by the state attribute.",
+			func(o metricsModel.Observer, s sql.DBStats) {
+				o.Observe(float64(s.Idle), pool, model.NewKeyValue(attrState, stateIdle))
+				o.Observe(float64(s.InUse), pool, model.NewKeyValue(attrState, stateUsed))
+			}},
+		{meter.ObservableUpDownCounter, metricConnectionsMax, "{connection}",
+			"The maximum number of open connections allowed, 0 when unlimited.",
+			observeValue(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })},
+		{meter.ObservableCounter, metricConnectionsWaits, "{wait}", "The total number of connections waited for.",
+			observeValue(func(s sql.DBStats) float64 { return float64(s.WaitCount) })},
+		{meter.ObservableCounter, metricConnectionsWaitTime, "ms", "The total time blocked waiting for a new connection.",
+			observeValue(func(s sql.DBStats) float64 { return float64(s.WaitDuration) / float64(time.Millisecond) })},
+		{meter.ObservableCounter, metricConnectionsClosed, "{connection}", "The total number of connections closed by the pool limits.",
+			observeValue(func(s sql.DBStats) float64 {
+				return float64(s.MaxIdleClosed + s.MaxIdleTimeClosed + s.MaxLifetimeClosed)
+			})},
+	}
+	registrations := make([]metricsModel.Registration, 0, len(instruments))
+	for _, instrument := range instruments {
+		observe := instrument.observe
+		r, err := instrument.register(instrument.name, func(_ context.Context, o metricsModel.Observer) error {
+			observe(o, db.Stats())
+			return nil
+		}, metricsModel.WithUnit(instrument.unit), metricsModel.WithDescription(instrument.description))
+		if err != nil {
+			return nil, errors.Join(err, unregister(registrations))
+		}
+		registrations = append(registrations, r)
+	}
+	return registrations, nil
+}
+
+func unregister(registrations []metricsModel.Registration) error {
+	var errs []error
+	for _, r := range registrations {
+		errs = append(errs, r.Unregister())
+	}
+	return errors.Join(errs...)
+}
+
+// ReportPoolStats reports the connection pool statistics of db with observable instruments of meter,
+// read at every collection and tagged with poolName. The statistics are reported until the returned
+// registrations are unregistered, e.g. when db is closed.
+func ReportPoolStats(db *sql.DB, meter metricsModel.Meter, poolName string) ([]metricsModel.Registration, error) {
+	return registerPoolInstruments(db, meter, model.NewKeyValue(attrPoolName, poolName))
+}
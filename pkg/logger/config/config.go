@@ -18,6 +18,14 @@ type Config struct {
 
 	// IncludeSource specifies whether to add source in the output. Default is false.
 	IncludeSource bool
+
+	// BaggageKeys are the trace baggage members added as fields to the loggers returned by
+	// logger.FromContext, when they are set in the context. By default, no member is added.
+	BaggageKeys []string
+
+	// MaxValueLength is the number of characters the string and error field values are truncated
+	// to, like the span attribute values. Zero or negative means no limit, the default.
+	MaxValueLength int
 }
 
 func (c *Config) GetLevel() model.Level {
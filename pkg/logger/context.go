@@ -9,6 +9,9 @@ func NewContextWithLogger(ctx context.Context, log model.Logger) context.Context
 	return context.WithValue(ctx, model.ContextKeyLogger, log)
 }
 
+// FromContext returns the logger of ctx, or the default logger when none is set. The baggage
+// members listed in config.Config.BaggageKeys are added to the returned logger as fields.
+//
 //nolint:ireturn // make the function generic
 func FromContext(ctx context.Context) model.Logger {
 	logger, ok := ctx.Value(model.ContextKeyLogger).(model.Logger)
@@ -19,5 +22,13 @@ func FromContext(ctx context.Context) model.Logger {
 		}
 		return dLog
 	}
+	if bl, ok := logger.(baggageLogger); ok {
+		return bl.WithBaggage(ctx)
+	}
 	return logger
 }
+
+// baggageLogger is implemented by the loggers adding the trace baggage of the context as fields.
+type baggageLogger interface {
+	WithBaggage(ctx context.Context) model.Logger
+}
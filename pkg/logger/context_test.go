@@ -5,9 +5,12 @@ import (
 	"github.com/nash-567/goObserve/pkg/logger"
 	"github.com/nash-567/goObserve/pkg/logger/config"
 	"github.com/nash-567/goObserve/pkg/logger/model"
+	tracingModel "github.com/nash-567/goObserve/pkg/tracing/model"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFromContext(t *testing.T) {
@@ -62,3 +65,48 @@ func TestNewContextWithLogger(t *testing.T) {
 	assert.NotNil(t, got)
 	assert.Same(t, got, log)
 }
+
+func TestFromContext_BaggageKeys(t *testing.T) {
+	t.Parallel()
+	output := new(strings.Builder)
+	log := logger.NewSlogLogger(&config.Config{
+		Output:      output,
+		Level:       "INFO",
+		BaggageKeys: []string{"tenant.id", "region"},
+	})
+	ctx := logger.NewContextWithLogger(context.Background(), log.WithField("component", "test"))
+	ctx, err := tracingModel.SetBaggage(ctx, "tenant.id", "acme")
+	require.NoError(t, err)
+	ctx, err = tracingModel.SetBaggage(ctx, "user.id", "42")
+	require.NoError(t, err)
+
+	logger.FromContext(ctx).Info(testMsgText)
+
+	assert.Contains(t, output.String(), `"tenant.id":"acme"`)
+	assert.Contains(t, output.String(), `"component":"test"`)
+	assert.NotContains(t, output.String(), "user.id")
+	assert.NotContains(t, output.String(), "region")
+}
+
+func TestFromContext_BaggageKeysAddedOnce(t *testing.T) {
+	t.Parallel()
+	output := new(strings.Builder)
+	log := logger.NewSlogLogger(&config.Config{
+		Output:      output,
+		Level:       "INFO",
+		BaggageKeys: []string{"tenant.id", "region"},
+	})
+	ctx, err := tracingModel.SetBaggage(context.Background(), "tenant.id", "acme")
+	require.NoError(t, err)
+
+	// e.g. middlewares storing the logger of the request with more fields
+	ctx = logger.NewContextWithLogger(ctx, log)
+	ctx = logger.NewContextWithLogger(ctx, logger.FromContext(ctx).WithField("component", "test"))
+	ctx, err = tracingModel.SetBaggage(ctx, "region", "eu")
+	require.NoError(t, err)
+	logger.FromContext(ctx).Info(testMsgText)
+
+	assert.Equal(t, 1, strings.Count(output.String(), `"tenant.id":"acme"`))
+	assert.Equal(t, 1, strings.Count(output.String(), `"region":"eu"`))
+	assert.Contains(t, output.String(), `"component":"test"`)
+}
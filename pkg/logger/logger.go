@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"os"
 	"sync"
+
+	"go.opentelemetry.io/otel/baggage"
 )
 
 //nolint:gochecknoglobals // to be used to return default logger when not set in context
@@ -34,6 +36,9 @@ type SlogLogger struct {
 	entry *slog.Logger
 	cfg   *config.Config
 	level *slog.LevelVar
+	// baggage holds the baggage keys already added as fields, so they are not added again
+	// when the logger is stored in a context and taken back from it with FromContext.
+	baggage map[string]struct{}
 }
 
 func NewSlogLogger(config *config.Config) *SlogLogger {
@@ -52,7 +57,7 @@ func buildLogger(config *config.Config, level slog.Leveler) *slog.Logger {
 	handler := slog.NewJSONHandler(config.Output, &slog.HandlerOptions{
 		AddSource:   config.IncludeSource,
 		Level:       level,
-		ReplaceAttr: replaceAttribute,
+		ReplaceAttr: newReplaceAttribute(config.MaxValueLength),
 	})
 
 	l := slog.New(handler)
@@ -79,6 +84,48 @@ func replaceAttribute(_ []string, a slog.Attr) slog.Attr {
 	return a
 }
 
+// newReplaceAttribute returns replaceAttribute, also truncating the string and error values of the
+// fields to maxValueLength characters when it is positive.
+func newReplaceAttribute(maxValueLength int) func([]string, slog.Attr) slog.Attr {
+	if maxValueLength <= 0 {
+		return replaceAttribute
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		a = replaceAttribute(groups, a)
+		if len(groups) == 0 && isBuiltinAttribute(a.Key) {
+			return a
+		}
+		switch a.Value.Kind() { //nolint:exhaustive // only strings and errors are truncated
+		case slog.KindString:
+			a.Value = slog.StringValue(truncate(a.Value.String(), maxValueLength))
+		case slog.KindAny:
+			if err, ok := a.Value.Any().(error); ok {
+				a.Value = slog.StringValue(truncate(err.Error(), maxValueLength))
+			}
+		}
+		return a
+	}
+}
+
+func isBuiltinAttribute(key string) bool {
+	return key == slog.TimeKey || key == slog.LevelKey || key == slog.MessageKey || key == slog.SourceKey
+}
+
+// truncate shortens s to maxLength characters, without splitting multi-byte characters.
+func truncate(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+	count := 0
+	for i := range s {
+		if count == maxLength {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
+
 // map contains custom slog levels.
 func getCustomLevelMap() map[slog.Leveler]string {
 	return map[slog.Leveler]string{
@@ -109,8 +156,10 @@ func (log *SlogLogger) Error(msg string) {
 
 func (log *SlogLogger) WithField(key string, value interface{}) model.Logger {
 	return &SlogLogger{
-		entry: log.entry.With(key, value),
-		level: log.level,
+		entry:   log.entry.With(key, value),
+		cfg:     log.cfg,
+		level:   log.level,
+		baggage: log.baggage,
 	}
 }
 
@@ -121,16 +170,58 @@ func (log *SlogLogger) WithFields(fields model.Fields) model.Logger {
 		sFields = append(sFields, key, value)
 	}
 	return &SlogLogger{
-		entry: log.entry.With(sFields...),
-		level: log.level,
+		entry:   log.entry.With(sFields...),
+		cfg:     log.cfg,
+		level:   log.level,
+		baggage: log.baggage,
 	}
 }
 
 //nolint:ireturn // implements model.Logger interface
 func (log *SlogLogger) WithError(err error) model.Logger {
 	return &SlogLogger{
-		entry: log.entry.With("error", err),
-		level: log.level,
+		entry:   log.entry.With("error", err),
+		cfg:     log.cfg,
+		level:   log.level,
+		baggage: log.baggage,
+	}
+}
+
+// WithBaggage adds the baggage members of ctx listed in config.Config.BaggageKeys as fields
+// and returns a new Logger. The members already added by a previous call are skipped, and the
+// logger is returned as is when there is nothing to add.
+//
+//nolint:ireturn // implements model.Logger interface
+func (log *SlogLogger) WithBaggage(ctx context.Context) model.Logger {
+	if log == nil || log.cfg == nil || len(log.cfg.BaggageKeys) == 0 {
+		return log
+	}
+	b := baggage.FromContext(ctx)
+	if b.Len() == 0 {
+		return log
+	}
+	sFields := make([]any, 0)
+	added := make(map[string]struct{}, len(log.baggage)+len(log.cfg.BaggageKeys))
+	for key := range log.baggage {
+		added[key] = struct{}{}
+	}
+	for _, key := range log.cfg.BaggageKeys {
+		if _, ok := added[key]; ok {
+			continue
+		}
+		if m := b.Member(key); m.Key() != "" {
+			sFields = append(sFields, key, m.Value())
+			added[key] = struct{}{}
+		}
+	}
+	if len(sFields) == 0 {
+		return log
+	}
+	return &SlogLogger{
+		entry:   log.entry.With(sFields...),
+		cfg:     log.cfg,
+		level:   log.level,
+		baggage: added,
 	}
 }
 
@@ -266,3 +266,18 @@ func testString(level model.Level, kv ...string) string {
 	}
 	return resp + `}`
 }
+
+func TestSlogLogger_MaxValueLength(t *testing.T) {
+	t.Parallel()
+	output := new(strings.Builder)
+	log := logger.NewSlogLogger(&config.Config{Output: output, Level: model.InfoLevel.String(), MaxValueLength: 4})
+	log.WithFields(model.Fields{"query": "SELECT * FROM users", "city": "Zürich", "count": 123456}).
+		WithError(errLogger).
+		Info("a message longer than the limit")
+
+	assert.Contains(t, output.String(), `"query":"SELE"`)
+	assert.Contains(t, output.String(), `"city":"Züri"`)
+	assert.Contains(t, output.String(), `"count":123456`)
+	assert.Contains(t, output.String(), `"error":"logg"`)
+	assert.Contains(t, output.String(), `"msg":"a message longer than the limit"`)
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	tracingConfig "github.com/nash-567/goObserve/pkg/tracing/config"
+	"time"
+)
+
+type MetricsConfig struct {
+	Enabled                bool                         `koanf:"Enabled"`
+	InstrumentationLibrary InstrumentationLibraryConfig `koanf:"InstrumentationLibrary"`
+	ExporterConfig         MetricExporterConfig         `koanf:"ExporterConfig"`
+	// RuntimeMetrics publishes the Go runtime and process metrics with the meter provider.
+	RuntimeMetrics RuntimeMetricsConfig `koanf:"RuntimeMetrics"`
+	// Views customize the metrics of the matching instruments. An instrument matching several
+	// views produces a metric for each of them.
+	Views []ViewConfig `koanf:"Views"`
+	// CardinalityLimit is the maximum number of series of every instrument, unlimited when zero.
+	// The measurements with new attribute sets past the limit are recorded in a single series with the
+	// otel.metric.overflow attribute, which counts towards the limit, counted by goobserve.metric.overflows
+	// and reported in a WARN log.
+	CardinalityLimit int `koanf:"CardinalityLimit"`
+	// CardinalityLimits overrides CardinalityLimit for the instruments by name.
+	CardinalityLimits map[string]int `koanf:"CardinalityLimits"`
+	// ExemplarFilter selects the measurements kept as exemplars, linking the metrics to the traces.
+	// The filter of the SDK is kept by default, which keeps the measurements recorded in a sampled span
+	// unless the OTEL_METRICS_EXEMPLAR_FILTER environment variable is set.
+	ExemplarFilter model.ExemplarFilter `koanf:"ExemplarFilter"`
+	// CallbackTimeout bounds the time the callbacks of the observable instruments take at every collection,
+	// 1s by default. The observations of a callback timing out are dropped for that collection.
+	CallbackTimeout time.Duration `koanf:"CallbackTimeout"`
+}
+
+// ViewConfig is the configuration for a view, selecting instruments by name and changing
+// how their metrics are aggregated, named and which attributes they keep.
+type ViewConfig struct {
+	// InstrumentName selects the instruments, "*" matches any sequence of characters and "?" any character.
+	InstrumentName string `koanf:"InstrumentName"`
+	// MeterName restricts the view to the instruments of a meter, all the meters by default.
+	MeterName string `koanf:"MeterName"`
+	// Rename is the new name of the metric. It requires an InstrumentName without wildcards.
+	Rename string `koanf:"Rename"`
+	// Description replaces the description of the metric.
+	Description string `koanf:"Description"`
+	// Aggregation replaces the aggregation of the instrument.
+	Aggregation AggregationConfig `koanf:"Aggregation"`
+	// AttributeAllowList keeps only these attribute keys on the measurements.
+	AttributeAllowList []string `koanf:"AttributeAllowList"`
+	// AttributeDenyList drops these attribute keys from the measurements, e.g. the high-cardinality ones.
+	// It cannot be combined with AttributeAllowList.
+	AttributeDenyList []string `koanf:"AttributeDenyList"`
+}
+
+// AggregationConfig is the configuration for the aggregation of a view.
+type AggregationConfig struct {
+	Type model.AggregationType `koanf:"Type"`
+	// BucketBoundaries are the increasing boundaries of the explicit_bucket_histogram buckets.
+	BucketBoundaries []float64 `koanf:"BucketBoundaries"`
+	// MaxSize is the maximum number of buckets of the base2_exponential_histogram, 160 by default.
+	MaxSize int32 `koanf:"MaxSize"`
+	// MaxScale is the maximum resolution of the base2_exponential_histogram, from -10 to 20, 20 when unset.
+	// It is a pointer so that 0 can be configured.
+	MaxScale *int32 `koanf:"MaxScale"`
+}
+
+// RuntimeMetricsConfig is the configuration for the Go runtime and process metrics. They are read
+// at every collection and named after the OpenTelemetry semantic conventions, e.g. go.goroutine.count
+// or process.cpu.time. The process metrics are read from /proc and only available on Linux.
+type RuntimeMetricsConfig struct {
+	Enabled bool `koanf:"Enabled"`
+}
+
+type InstrumentationLibraryConfig struct {
+	Name      string `koanf:"Name"`
+	SchemaURL string `koanf:"SchemaURL"`
+	Version   string `koanf:"Version"`
+}
+
+// MetricExporterConfig is the configuration for the metric exporter.
+type MetricExporterConfig struct {
+	Type model.MetricExporterType `koanf:"Type"`
+	// in case of stdout exporter, EndpointURL, Timeout and RetryConfig are ignored
+	EndpointURL string                    `koanf:"EndpointURL"`
+	Timeout     time.Duration             `koanf:"Timeout"`
+	RetryConfig MetricExporterRetryConfig `koanf:"RetryConfig"`
+	// Interval is the time between two exports of the periodic reader, 60s by default.
+	Interval time.Duration `koanf:"Interval"`
+	// Headers are sent with every export request of the http and grpc exporters, e.g. bearer tokens or API keys.
+	Headers     map[string]string       `koanf:"Headers"`
+	Compression model.CompressionType   `koanf:"Compression"`
+	TLS         MetricExporterTLSConfig `koanf:"TLS"`
+	// Insecure disables transport security, TLS is ignored when set. Meant for local development.
+	Insecure bool `koanf:"Insecure"`
+	// StatsD is the configuration of the statsd exporter, whose EndpointURL is the host:port address of the agent.
+	StatsD StatsDConfig `koanf:"StatsD"`
+}
+
+// MetricExporterTLSConfig is the configuration for the metric exporter transport security,
+// the same as for the trace exporter.
+type MetricExporterTLSConfig = tracingConfig.TraceExporterTLSConfig
+
+// StatsDConfig is the configuration for the statsd exporter.
+type StatsDConfig struct {
+	// Prefix is prepended to the metric names, e.g. "checkout.".
+	Prefix string `koanf:"Prefix"`
+	// MaxPacketSize is the maximum size of the UDP packets, 1432 bytes by default to fit in the Ethernet MTU.
+	MaxPacketSize int `koanf:"MaxPacketSize"`
+}
+
+// MetricExporterRetryConfig is the configuration for the metric exporter retry.
+type MetricExporterRetryConfig struct {
+	Enabled         bool          `koanf:"Enabled"`
+	InitialInterval time.Duration `koanf:"InitialInterval"`
+	MaxInterval     time.Duration `koanf:"MaxInterval"`
+	MaxElapsedTime  time.Duration `koanf:"MaxElapsedTime"`
+}
@@ -0,0 +1,270 @@
+package metricstest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/metrics/otelmeter"
+	"go.opentelemetry.io/otel/attribute"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Reader keeps the metrics of its meter in memory and collects them on demand, so tests can
+// check the measurements recorded by the code under test:
+//
+//	reader := metricstest.NewReader(t, nil)
+//	handler := NewHandler(reader.Meter())
+//	...
+//	reader.AssertCounter(t, "orders.created", 1, tracingModel.NewKeyValue("country", "FR"))
+//
+// The metrics are cumulative: the assertions check the totals since the reader was created.
+type Reader struct {
+	reader        *sdkMetric.ManualReader
+	meterProvider *sdkMetric.MeterProvider
+	meter         *otelmeter.Meter
+}
+
+// NewReader creates a reader and the meter whose metrics it collects, with the meter provider of
+// otelmeter.NewMeterProvider. cfg configures both, e.g. with views or cardinality limits, and is used
+// even when not enabled. An empty configuration is used when nil. The test fails if the configuration
+// is invalid, and the meter provider is shut down when the test ends.
+func NewReader(t testing.TB, cfg *config.MetricsConfig) *Reader {
+	t.Helper()
+	enabled := config.MetricsConfig{}
+	if cfg != nil {
+		enabled = *cfg
+	}
+	enabled.Enabled = true
+	reader := sdkMetric.NewManualReader()
+	mp, err := otelmeter.NewMeterProvider(&enabled, nil, "metricstest", otelmeter.WithReader(reader))
+	if err != nil {
+		t.Fatalf("failed to create meter provider: %v", err)
+	}
+	sdkMP, ok := mp.(*sdkMetric.MeterProvider)
+	if !ok {
+		t.Fatalf("unexpected meter provider %T", mp)
+	}
+	t.Cleanup(func() { _ = sdkMP.Shutdown(context.Background()) })
+	return &Reader{
+		reader:        reader,
+		meterProvider: sdkMP,
+		meter:         otelmeter.NewMeter(&enabled, sdkMP),
+	}
+}
+
+// Meter returns the meter whose metrics are collected.
+func (r *Reader) Meter() *otelmeter.Meter {
+	return r.meter
+}
+
+// MeterProvider returns the meter provider whose metrics are collected, for the code creating its own meters.
+func (r *Reader) MeterProvider() *sdkMetric.MeterProvider {
+	return r.meterProvider
+}
+
+// Collect collects the metrics, the test fails if the collection fails.
+func (r *Reader) Collect(t testing.TB) metricdata.ResourceMetrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := r.reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+	return rm
+}
+
+// Counter returns the total of the counter or up-down counter for exactly the attributes.
+// ok is false when the metric has no such data point.
+func (r *Reader) Counter(t testing.TB, name string, attributes ...model.KeyValue) (float64, bool) {
+	t.Helper()
+	switch data := r.metric(t, name).(type) {
+	case metricdata.Sum[float64]:
+		return pointValue(data.DataPoints, attributes)
+	case metricdata.Sum[int64]:
+		value, ok := pointValue(data.DataPoints, attributes)
+		return float64(value), ok
+	default:
+		return 0, false
+	}
+}
+
+// Gauge returns the last value of the gauge for exactly the attributes.
+// ok is false when the metric has no such data point.
+func (r *Reader) Gauge(t testing.TB, name string, attributes ...model.KeyValue) (float64, bool) {
+	t.Helper()
+	switch data := r.metric(t, name).(type) {
+	case metricdata.Gauge[float64]:
+		return pointValue(data.DataPoints, attributes)
+	case metricdata.Gauge[int64]:
+		value, ok := pointValue(data.DataPoints, attributes)
+		return float64(value), ok
+	default:
+		return 0, false
+	}
+}
+
+// Histogram returns the count and sum of the histogram for exactly the attributes.
+// ok is false when the metric has no such data point.
+func (r *Reader) Histogram(t testing.TB, name string, attributes ...model.KeyValue) (count uint64, sum float64, ok bool) {
+	t.Helper()
+	switch data := r.metric(t, name).(type) {
+	case metricdata.Histogram[float64]:
+		if dp, found := find(data.DataPoints, attributes, histogramAttributes[float64]); found {
+			return dp.Count, dp.Sum, true
+		}
+	case metricdata.Histogram[int64]:
+		if dp, found := find(data.DataPoints, attributes, histogramAttributes[int64]); found {
+			return dp.Count, float64(dp.Sum), true
+		}
+	}
+	return 0, 0, false
+}
+
+// AssertCounter checks the total of the counter or up-down counter for exactly the attributes.
+func (r *Reader) AssertCounter(t testing.TB, name string, want float64, attributes ...model.KeyValue) bool {
+	t.Helper()
+	got, ok := r.Counter(t, name, attributes...)
+	return r.check(t, name, attributes, ok, got == want, fmt.Sprint(want), fmt.Sprint(got))
+}
+
+// AssertGauge checks the last value of the gauge for exactly the attributes.
+func (r *Reader) AssertGauge(t testing.TB, name string, want float64, attributes ...model.KeyValue) bool {
+	t.Helper()
+	got, ok := r.Gauge(t, name, attributes...)
+	return r.check(t, name, attributes, ok, got == want, fmt.Sprint(want), fmt.Sprint(got))
+}
+
+// AssertHistogram checks the count and sum of the histogram for exactly the attributes.
+func (r *Reader) AssertHistogram(t testing.TB, name string, wantCount uint64, wantSum float64, attributes ...model.KeyValue) bool {
+	t.Helper()
+	count, sum, ok := r.Histogram(t, name, attributes...)
+	return r.check(t, name, attributes, ok, count == wantCount && sum == wantSum,
+		fmt.Sprintf("count %d, sum %v", wantCount, wantSum),
+		fmt.Sprintf("count %d, sum %v", count, sum))
+}
+
+func (r *Reader) check(t testing.TB, name string, attributes []model.KeyValue, ok, equal bool, want, got string) bool {
+	t.Helper()
+	if !ok {
+		t.Errorf("metric %s has no data point with attributes {%s}, %s",
+			name, renderAttributes(toSet(attributes)), r.describe(t, name))
+		return false
+	}
+	if !equal {
+		t.Errorf("metric %s with attributes {%s}: want %s, got %s", name, renderAttributes(toSet(attributes)), want, got)
+		return false
+	}
+	return true
+}
+
+// metric returns the data of the metric name, nil when it was not recorded.
+//
+//nolint:ireturn // the aggregation depends on the instrument
+func (r *Reader) metric(t testing.TB, name string) metricdata.Aggregation {
+	t.Helper()
+	rm := r.Collect(t)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	return nil
+}
+
+// describe lists the recorded metrics, or the attribute sets of the metric when it was recorded,
+// to help fixing a failed assertion.
+func (r *Reader) describe(t testing.TB, name string) string {
+	t.Helper()
+	rm := r.Collect(t)
+	var names, sets []string
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			names = append(names, m.Name)
+			if m.Name == name {
+				sets = dataPointAttributes(m.Data)
+			}
+		}
+	}
+	if sets == nil {
+		sort.Strings(names)
+		return "recorded metrics: " + strings.Join(names, ", ")
+	}
+	sort.Strings(sets)
+	return "recorded attributes: " + strings.Join(sets, ", ")
+}
+
+func dataPointAttributes(data metricdata.Aggregation) []string {
+	var sets []string
+	add := func(set attribute.Set) { sets = append(sets, "{"+renderAttributes(set)+"}") }
+	switch data := data.(type) {
+	case metricdata.Sum[float64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes)
+		}
+	case metricdata.Sum[int64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes)
+		}
+	case metricdata.Gauge[float64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes)
+		}
+	case metricdata.Gauge[int64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes)
+		}
+	case metricdata.Histogram[float64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes)
+		}
+	case metricdata.Histogram[int64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes)
+		}
+	}
+	return sets
+}
+
+func pointValue[N int64 | float64](points []metricdata.DataPoint[N], attributes []model.KeyValue) (N, bool) {
+	dp, ok := find(points, attributes, func(dp metricdata.DataPoint[N]) attribute.Set { return dp.Attributes })
+	return dp.Value, ok
+}
+
+func histogramAttributes[N int64 | float64](dp metricdata.HistogramDataPoint[N]) attribute.Set {
+	return dp.Attributes
+}
+
+func find[P any](points []P, attributes []model.KeyValue, attributesOf func(P) attribute.Set) (P, bool) {
+	want := toSet(attributes)
+	for _, dp := range points {
+		set := attributesOf(dp)
+		if set.Equals(&want) {
+			return dp, true
+		}
+	}
+	var zero P
+	return zero, false
+}
+
+func toSet(attributes []model.KeyValue) attribute.Set {
+	attrs := make([]attribute.KeyValue, len(attributes))
+	for i, kv := range attributes {
+		attrs[i] = kv.GetAttributeKeyValue()
+	}
+	return attribute.NewSet(attrs...)
+}
+
+func renderAttributes(set attribute.Set) string {
+	pairs := make([]string, 0, set.Len())
+	for _, kv := range set.ToSlice() {
+		pairs = append(pairs, string(kv.Key)+"="+kv.Value.Emit())
+	}
+	return strings.Join(pairs, ",")
+}
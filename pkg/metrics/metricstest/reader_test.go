@@ -0,0 +1,105 @@
+package metricstest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/metricstest"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	tracingModel "github.com/nash-567/goObserve/pkg/tracing/model"
+)
+
+// recordingT keeps the errors reported by the assertions instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func record(t *testing.T, meter model.Meter) {
+	t.Helper()
+	ctx := context.Background()
+	fr := tracingModel.NewKeyValue("country", "FR")
+	orders, err := meter.Counter("orders.created")
+	require.NoError(t, err)
+	orders.Add(ctx, 2, fr)
+	orders.Add(ctx, 1, fr)
+	orders.Add(ctx, 5)
+	inFlight, err := meter.UpDownCounter("orders.in_flight")
+	require.NoError(t, err)
+	inFlight.Add(ctx, 3)
+	inFlight.Add(ctx, -1)
+	duration, err := meter.Histogram("orders.duration")
+	require.NoError(t, err)
+	duration.Record(ctx, 0.25, fr)
+	duration.Record(ctx, 0.5, fr)
+	temperature, err := meter.Gauge("warehouse.temperature")
+	require.NoError(t, err)
+	temperature.Record(ctx, 19, fr)
+	temperature.Record(ctx, 21, fr)
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+	reader := metricstest.NewReader(t, nil)
+	record(t, reader.Meter())
+	fr := tracingModel.NewKeyValue("country", "FR")
+
+	assert.True(t, reader.AssertCounter(t, "orders.created", 3, fr))
+	assert.True(t, reader.AssertCounter(t, "orders.created", 5))
+	assert.True(t, reader.AssertCounter(t, "orders.in_flight", 2))
+	assert.True(t, reader.AssertHistogram(t, "orders.duration", 2, 0.75, fr))
+	assert.True(t, reader.AssertGauge(t, "warehouse.temperature", 21, fr))
+
+	total, ok := reader.Counter(t, "orders.created", fr)
+	assert.True(t, ok)
+	assert.InDelta(t, 3, total, 0)
+	_, ok = reader.Counter(t, "orders.created", tracingModel.NewKeyValue("country", "DE"))
+	assert.False(t, ok)
+	_, _, ok = reader.Histogram(t, "orders.created")
+	assert.False(t, ok)
+}
+
+func TestReader_Failures(t *testing.T) {
+	t.Parallel()
+	reader := metricstest.NewReader(t, &config.MetricsConfig{})
+	record(t, reader.Meter())
+	rt := &recordingT{TB: t}
+
+	assert.False(t, reader.AssertCounter(rt, "orders.created", 4, tracingModel.NewKeyValue("country", "FR")))
+	assert.False(t, reader.AssertCounter(rt, "orders.created", 1, tracingModel.NewKeyValue("country", "DE")))
+	assert.False(t, reader.AssertHistogram(rt, "orders.duration", 1, 0.25))
+	assert.False(t, reader.AssertGauge(rt, "unknown", 1))
+	assert.Equal(t, []string{
+		"metric orders.created with attributes {country=FR}: want 4, got 3",
+		"metric orders.created has no data point with attributes {country=DE}, recorded attributes: {country=FR}, {}",
+		"metric orders.duration has no data point with attributes {}, recorded attributes: {country=FR}",
+		"metric unknown has no data point with attributes {}, recorded metrics: orders.created, orders.duration, " +
+			"orders.in_flight, warehouse.temperature",
+	}, rt.errors)
+}
+
+func TestReader_Views(t *testing.T) {
+	t.Parallel()
+	reader := metricstest.NewReader(t, &config.MetricsConfig{
+		Views: []config.ViewConfig{{InstrumentName: "orders.created", Rename: "orders"}},
+	})
+	record(t, reader.Meter())
+
+	assert.True(t, reader.AssertCounter(t, "orders", 3, tracingModel.NewKeyValue("country", "FR")))
+	meter := reader.MeterProvider().Meter("other")
+	counter, err := meter.Int64Counter("other.calls")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 2)
+	assert.True(t, reader.AssertCounter(t, "other.calls", 2))
+}
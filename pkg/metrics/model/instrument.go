@@ -0,0 +1,68 @@
+package model
+
+// InstrumentOption applies an option to an InstrumentConfig.
+type InstrumentOption interface {
+	applyInstrument(InstrumentConfig) InstrumentConfig
+}
+
+// InstrumentConfig is a group of options for an instrument.
+type InstrumentConfig struct {
+	description      string
+	unit             string
+	bucketBoundaries []float64
+}
+
+// NewInstrumentConfig applies all the options to a returned InstrumentConfig.
+func NewInstrumentConfig(options ...InstrumentOption) InstrumentConfig {
+	var c InstrumentConfig
+	for _, option := range options {
+		c = option.applyInstrument(c)
+	}
+	return c
+}
+
+// Description describes what the instrument measures.
+func (cfg *InstrumentConfig) Description() string {
+	return cfg.description
+}
+
+// Unit is the UCUM unit of the measurements, e.g. "ms" or "By".
+func (cfg *InstrumentConfig) Unit() string {
+	return cfg.unit
+}
+
+// BucketBoundaries are the explicit bucket boundaries of a histogram.
+func (cfg *InstrumentConfig) BucketBoundaries() []float64 {
+	return cfg.bucketBoundaries
+}
+
+type instrumentOptionFunc func(InstrumentConfig) InstrumentConfig
+
+func (fn instrumentOptionFunc) applyInstrument(cfg InstrumentConfig) InstrumentConfig {
+	return fn(cfg)
+}
+
+// WithDescription sets the description of the instrument.
+func WithDescription(description string) InstrumentOption {
+	return instrumentOptionFunc(func(cfg InstrumentConfig) InstrumentConfig {
+		cfg.description = description
+		return cfg
+	})
+}
+
+// WithUnit sets the UCUM unit of the instrument, e.g. "ms", "s" or "By".
+func WithUnit(unit string) InstrumentOption {
+	return instrumentOptionFunc(func(cfg InstrumentConfig) InstrumentConfig {
+		cfg.unit = unit
+		return cfg
+	})
+}
+
+// WithBucketBoundaries sets the explicit bucket boundaries of a histogram, in increasing order.
+// It is ignored by the other instruments.
+func WithBucketBoundaries(boundaries ...float64) InstrumentOption {
+	return instrumentOptionFunc(func(cfg InstrumentConfig) InstrumentConfig {
+		cfg.bucketBoundaries = boundaries
+		return cfg
+	})
+}
@@ -1 +1,57 @@
 package model
+
+import "context"
+
+type Meter interface {
+	// Counter creates a monotonic counter, e.g. the number of handled requests.
+	Counter(name string, opts ...InstrumentOption) (Counter, error)
+	// UpDownCounter creates a counter that can also decrease, e.g. the number of active requests.
+	UpDownCounter(name string, opts ...InstrumentOption) (UpDownCounter, error)
+	// Histogram creates an instrument recording the distribution of values, e.g. request durations.
+	Histogram(name string, opts ...InstrumentOption) (Histogram, error)
+	// Gauge creates an instrument recording the current value of something, e.g. a temperature.
+	Gauge(name string, opts ...InstrumentOption) (Gauge, error)
+	// ObservableCounter creates a monotonic counter whose total is reported by callback at every
+	// collection, e.g. the number of bytes read from a reader keeping count.
+	ObservableCounter(name string, callback Callback, opts ...InstrumentOption) (Registration, error)
+	// ObservableUpDownCounter creates a counter that can also decrease, whose total is reported by
+	// callback at every collection, e.g. the depth of a queue.
+	ObservableUpDownCounter(name string, callback Callback, opts ...InstrumentOption) (Registration, error)
+	// ObservableGauge creates an instrument whose current value is reported by callback at every
+	// collection, e.g. the size of a cache.
+	ObservableGauge(name string, callback Callback, opts ...InstrumentOption) (Registration, error)
+}
+
+// Callback reports the current values of an observable instrument to observer, once per attribute set.
+// It is called at every collection, with a context canceled when it takes too long.
+type Callback func(ctx context.Context, observer Observer) error
+
+type Observer interface {
+	// Observe reports the current value of the instrument for the attributes.
+	Observe(value float64, attributes ...KeyValue)
+}
+
+type Registration interface {
+	// Unregister stops calling the callback, the instrument is no longer reported.
+	Unregister() error
+}
+
+type Counter interface {
+	// Add increments the counter by incr, which must not be negative.
+	Add(ctx context.Context, incr float64, attributes ...KeyValue)
+}
+
+type UpDownCounter interface {
+	// Add adds value to the counter, a negative value decrements it.
+	Add(ctx context.Context, value float64, attributes ...KeyValue)
+}
+
+type Histogram interface {
+	// Record adds value to the distribution.
+	Record(ctx context.Context, value float64, attributes ...KeyValue)
+}
+
+type Gauge interface {
+	// Record sets the current value.
+	Record(ctx context.Context, value float64, attributes ...KeyValue)
+}
@@ -0,0 +1,62 @@
+package model
+
+import tracingModel "github.com/nash-567/goObserve/pkg/tracing/model"
+
+// KeyValue is an attribute of a measurement. It is the same type as the span attributes,
+// so the same attributes can be used for both, create them with tracing/model.NewKeyValue.
+type KeyValue = tracingModel.KeyValue
+
+// CompressionType is an enum for the compression applied to exported payloads, the same as for the traces.
+type CompressionType = tracingModel.CompressionType
+
+const (
+	CompressionTypeNone = tracingModel.CompressionTypeNone
+	CompressionTypeGzip = tracingModel.CompressionTypeGzip
+)
+
+// MetricExporterType is an enum for the type of metric exporter.
+type MetricExporterType int8
+
+const (
+	MetricExporterTypeStdout MetricExporterType = iota
+	MetricExporterTypeHTTP
+	MetricExporterTypeGRPC
+	MetricExporterTypeStatsD
+)
+
+//go:generate enumer -type=MetricExporterType -json -text -yaml -trimprefix=MetricExporterType -transform=snake -output=enum_metricexportertype_gen.go
+
+// AggregationType is an enum for the aggregation of the instruments matching a view.
+type AggregationType int8
+
+const (
+	// AggregationTypeDefault keeps the default aggregation of the instrument.
+	AggregationTypeDefault AggregationType = iota
+	// AggregationTypeExplicitBucketHistogram aggregates the measurements in buckets with explicit boundaries.
+	AggregationTypeExplicitBucketHistogram
+	// AggregationTypeBase2ExponentialHistogram aggregates the measurements in buckets of exponentially
+	// growing size, adjusted to the range of the measurements.
+	AggregationTypeBase2ExponentialHistogram
+	// AggregationTypeDrop drops the measurements.
+	AggregationTypeDrop
+)
+
+//go:generate enumer -type=AggregationType -json -text -yaml -trimprefix=AggregationType -transform=snake -output=enum_aggregationtype_gen.go
+
+// ExemplarFilter is an enum for the measurements kept as exemplars, the sample measurements
+// of a metric linked to the span they were recorded in.
+type ExemplarFilter int8
+
+const (
+	// ExemplarFilterDefault keeps the filter of the SDK, set by the OTEL_METRICS_EXEMPLAR_FILTER
+	// environment variable, trace based when it is not set.
+	ExemplarFilterDefault ExemplarFilter = iota
+	// ExemplarFilterTraceBased keeps the measurements recorded in a sampled span.
+	ExemplarFilterTraceBased
+	// ExemplarFilterAlwaysOn keeps any measurement, the ones recorded outside a span have no trace ID.
+	ExemplarFilterAlwaysOn
+	// ExemplarFilterAlwaysOff keeps no exemplars.
+	ExemplarFilterAlwaysOff
+)
+
+//go:generate enumer -type=ExemplarFilter -json -text -yaml -trimprefix=ExemplarFilter -transform=snake -output=enum_exemplarfilter_gen.go
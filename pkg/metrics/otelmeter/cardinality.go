@@ -0,0 +1,240 @@
+package otelmeter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/logger"
+	logModel "github.com/nash-567/goObserve/pkg/logger/model"
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/metrics/statsd"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+const (
+	// attrOverflow is the only attribute of the series the attribute sets past the cardinality limit fold into.
+	// See https://opentelemetry.io/docs/specs/otel/metrics/sdk/#cardinality-limits.
+	attrOverflow = "otel.metric.overflow"
+	attrMetric   = "metric.name"
+
+	metricOverflows = "goobserve.metric.overflows"
+
+	overflowWarnInterval = time.Minute
+)
+
+//nolint:gochecknoglobals // immutable attribute set
+var overflowSet = attribute.NewSet(attribute.Bool(attrOverflow, true))
+
+// cardinalityLimiter bounds the number of series of an instrument, the overflow series included like
+// the SDK limit does: limit-1 attribute sets are kept, then the measurements with new attribute sets
+// are recorded with the overflow attributes instead, they are counted in overflows and a warning is
+// logged at most once per overflowWarnInterval.
+//
+// The attribute sets are counted after the attribute filters of the views matching the instrument,
+// so the attributes dropped by the views do not count towards the limit. The sets are forgotten by
+// reset, at every collection of the instruments whose series are not kept across collections.
+type cardinalityLimiter struct {
+	name      string
+	limit     int
+	filter    attribute.Filter
+	overflows atomic.Int64
+	lastWarn  atomic.Int64
+
+	mu   sync.RWMutex
+	sets map[attribute.Distinct]struct{}
+}
+
+// newCardinalityLimiter returns nil when limit is not positive, nil limiters keep all the attribute sets.
+// filter selects the attributes identifying a set, all of them when nil.
+func newCardinalityLimiter(name string, limit int, filter attribute.Filter) *cardinalityLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &cardinalityLimiter{
+		name:   name,
+		limit:  limit,
+		filter: filter,
+		sets:   make(map[attribute.Distinct]struct{}, limit),
+	}
+}
+
+// apply returns the set of attrs, or the overflow set when attrs is a new attribute set past the limit.
+// The set is passed to the SDK as is, so limiting only costs a map lookup under a read lock for the
+// known sets, and a second set when the views filter the attributes.
+func (l *cardinalityLimiter) apply(ctx context.Context, attrs []attribute.KeyValue) attribute.Set {
+	set := attribute.NewSet(attrs...)
+	if l == nil {
+		return set
+	}
+	key := set.Equivalent()
+	if l.filter != nil {
+		filtered, _ := set.Filter(l.filter)
+		key = filtered.Equivalent()
+	}
+
+	l.mu.RLock()
+	_, ok := l.sets[key]
+	l.mu.RUnlock()
+	if ok {
+		return set
+	}
+	l.mu.Lock()
+	// the last series of the limit is the overflow one
+	if _, ok = l.sets[key]; ok || len(l.sets) < l.limit-1 {
+		l.sets[key] = struct{}{}
+		l.mu.Unlock()
+		return set
+	}
+	l.mu.Unlock()
+
+	l.overflows.Add(1)
+	now := time.Now().UnixNano()
+	if last := l.lastWarn.Load(); time.Duration(now-last) >= overflowWarnInterval && l.lastWarn.CompareAndSwap(last, now) {
+		logger.FromContext(ctx).WithFields(logModel.Fields{
+			attrMetric:          l.name,
+			"cardinality_limit": l.limit,
+		}).Warn("metric cardinality limit reached, new attribute sets are recorded in the overflow series")
+	}
+	return overflowSet
+}
+
+// reset forgets the attribute sets, the next ones are counted from zero.
+func (l *cardinalityLimiter) reset() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	clear(l.sets)
+	l.mu.Unlock()
+}
+
+// cardinalityLimiters creates the limiters of the instruments of a meter, one per instrument name
+// so that all the handles and callbacks of an instrument share the same attribute sets.
+type cardinalityLimiters struct {
+	meterName   string
+	limit       int
+	limits      map[string]int
+	views       []sdkMetric.View
+	temporality sdkMetric.TemporalitySelector
+
+	mu         sync.Mutex
+	byName     map[string]*cardinalityLimiter
+	resettable []*cardinalityLimiter
+	all        []*cardinalityLimiter
+}
+
+func newCardinalityLimiters(cfg *config.MetricsConfig) *cardinalityLimiters {
+	// invalid views are reported by NewMeterProvider, the limiters ignore them
+	views, _ := newViews(cfg.Views)
+	temporality := sdkMetric.DefaultTemporalitySelector
+	if cfg.ExporterConfig.Type == model.MetricExporterTypeStatsD {
+		temporality = statsd.TemporalitySelector
+	}
+	return &cardinalityLimiters{
+		meterName:   cfg.InstrumentationLibrary.Name,
+		limit:       cfg.CardinalityLimit,
+		limits:      cfg.CardinalityLimits,
+		views:       views,
+		temporality: temporality,
+		byName:      map[string]*cardinalityLimiter{},
+	}
+}
+
+// forSync returns the limiter of the synchronous instrument name, reset at every collection
+// when the temporality of the exporter is delta.
+func (ls *cardinalityLimiters) forSync(name string, kind sdkMetric.InstrumentKind) *cardinalityLimiter {
+	return ls.forInstrument(name, kind, ls.temporality(kind) == metricdata.DeltaTemporality)
+}
+
+// forObservable returns the limiter of the observable instrument name, shared by its callbacks.
+// It is reset at every collection, as only the attribute sets observed by the callbacks of the
+// collection are reported.
+func (ls *cardinalityLimiters) forObservable(name string, kind sdkMetric.InstrumentKind) *cardinalityLimiter {
+	return ls.forInstrument(name, kind, true)
+}
+
+func (ls *cardinalityLimiters) forInstrument(name string, kind sdkMetric.InstrumentKind, resettable bool) *cardinalityLimiter {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if l, ok := ls.byName[name]; ok {
+		return l
+	}
+	l := ls.newLimiter(name, kind)
+	ls.byName[name] = l
+	if l != nil && resettable {
+		ls.resettable = append(ls.resettable, l)
+	}
+	return l
+}
+
+func (ls *cardinalityLimiters) newLimiter(name string, kind sdkMetric.InstrumentKind) *cardinalityLimiter {
+	limit, ok := ls.limits[name]
+	if !ok {
+		limit = ls.limit
+	}
+	if limit <= 0 {
+		return nil
+	}
+	l := newCardinalityLimiter(name, limit, ls.viewFilter(name, kind))
+	ls.all = append(ls.all, l)
+	return l
+}
+
+// viewFilter returns a filter keeping the attributes kept by any of the views matching the
+// instrument, or nil when one of them keeps all the attributes.
+func (ls *cardinalityLimiters) viewFilter(name string, kind sdkMetric.InstrumentKind) attribute.Filter {
+	instrument := sdkMetric.Instrument{Name: name, Kind: kind, Scope: instrumentation.Scope{Name: ls.meterName}}
+	var filters []attribute.Filter
+	for _, view := range ls.views {
+		stream, ok := view(instrument)
+		if !ok {
+			continue
+		}
+		if _, drop := stream.Aggregation.(sdkMetric.AggregationDrop); drop {
+			continue
+		}
+		if stream.AttributeFilter == nil {
+			return nil
+		}
+		filters = append(filters, stream.AttributeFilter)
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+	return func(kv attribute.KeyValue) bool {
+		for _, filter := range filters {
+			if filter(kv) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// observe reports the overflows of every instrument, then resets the limiters of the delta and
+// observable instruments: it is the callback of an instrument, which the SDK runs at the beginning of
+// every collection of every reader, before the callbacks registered by registerCallback.
+func (ls *cardinalityLimiters) observe(_ context.Context, o metric.Float64Observer) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	overflows := map[string]int64{}
+	for _, l := range ls.all {
+		overflows[l.name] += l.overflows.Load()
+	}
+	for name, count := range overflows {
+		if count > 0 {
+			o.Observe(float64(count), metric.WithAttributes(attribute.String(attrMetric, name)))
+		}
+	}
+	for _, l := range ls.resettable {
+		l.reset()
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package otelmeter_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/nash-567/goObserve/pkg/logger"
+	logConfig "github.com/nash-567/goObserve/pkg/logger/config"
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/metrics/otelmeter"
+	"github.com/nash-567/goObserve/pkg/metrics/statsd"
+	tracingModel "github.com/nash-567/goObserve/pkg/tracing/model"
+)
+
+// sums returns the values of the counter data points by their rendered attributes.
+func sums(t *testing.T, reader *sdkMetric.ManualReader, name string) map[string]float64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	values := map[string]float64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[float64]).DataPoints {
+				var attrs []string
+				for _, kv := range dp.Attributes.ToSlice() {
+					attrs = append(attrs, string(kv.Key)+"="+kv.Value.Emit())
+				}
+				values[strings.Join(attrs, ",")] = dp.Value
+			}
+		}
+	}
+	return values
+}
+
+func TestMeter_CardinalityLimit(t *testing.T) {
+	t.Parallel()
+	reader := sdkMetric.NewManualReader()
+	meter := otelmeter.NewMeter(&config.MetricsConfig{
+		// two attribute sets and the overflow series
+		CardinalityLimit:  3,
+		CardinalityLimits: map[string]int{"unbounded": 0},
+	}, sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)))
+	logs := new(strings.Builder)
+	ctx := logger.NewContextWithLogger(context.Background(),
+		logger.NewSlogLogger(&logConfig.Config{Output: logs, Level: "INFO"}))
+
+	logins, err := meter.Counter("logins")
+	require.NoError(t, err)
+	unbounded, err := meter.Counter("unbounded")
+	require.NoError(t, err)
+	for i := range 5 {
+		userID := tracingModel.NewKeyValue("user.id", strconv.Itoa(i))
+		logins.Add(ctx, 1, userID)
+		logins.Add(ctx, 1, userID)
+		unbounded.Add(ctx, 1, userID)
+	}
+
+	assert.Equal(t, map[string]float64{
+		"user.id=0":                 2,
+		"user.id=1":                 2,
+		"otel.metric.overflow=true": 6,
+	}, sums(t, reader, "logins"))
+	assert.Len(t, sums(t, reader, "unbounded"), 5)
+	assert.Equal(t, map[string]float64{"metric.name=logins": 6}, sums(t, reader, "goobserve.metric.overflows"))
+
+	// the warning is rate limited
+	assert.Equal(t, 1, strings.Count(logs.String(), "metric cardinality limit reached"))
+	assert.Contains(t, logs.String(), `"metric.name":"logins"`)
+	assert.Contains(t, logs.String(), `"cardinality_limit":3`)
+}
+
+func TestMeter_CardinalityLimitSharedByName(t *testing.T) {
+	t.Parallel()
+	reader := sdkMetric.NewManualReader()
+	meter := otelmeter.NewMeter(&config.MetricsConfig{CardinalityLimit: 3},
+		sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)))
+
+	// the handles of the same instrument share its limit
+	for i := range 3 {
+		logins, err := meter.Counter("logins")
+		require.NoError(t, err)
+		logins.Add(context.Background(), 1, tracingModel.NewKeyValue("user.id", strconv.Itoa(i)))
+	}
+
+	assert.Equal(t, map[string]float64{
+		"user.id=0":                 1,
+		"user.id=1":                 1,
+		"otel.metric.overflow=true": 1,
+	}, sums(t, reader, "logins"))
+}
+
+func TestMeter_CardinalityLimitAfterViews(t *testing.T) {
+	t.Parallel()
+	cfg := &config.MetricsConfig{
+		CardinalityLimit: 2,
+		Views:            []config.ViewConfig{{InstrumentName: "log*", AttributeDenyList: []string{"user.id"}}},
+	}
+	reader := sdkMetric.NewManualReader()
+	views, err := otelmeter.NewMeterProvider(&config.MetricsConfig{Enabled: true, Views: cfg.Views}, nil, "test",
+		otelmeter.WithReader(reader))
+	require.NoError(t, err)
+	meter := otelmeter.NewMeter(cfg, views)
+
+	logins, err := meter.Counter("logins")
+	require.NoError(t, err)
+	region := tracingModel.NewKeyValue("region", "eu")
+	for i := range 3 {
+		// the user.id attribute is dropped by the view, so it does not count towards the limit
+		logins.Add(context.Background(), 1, region, tracingModel.NewKeyValue("user.id", strconv.Itoa(i)))
+	}
+	logins.Add(context.Background(), 1, tracingModel.NewKeyValue("region", "us"))
+
+	assert.Equal(t, map[string]float64{
+		"region=eu":                 3,
+		"otel.metric.overflow=true": 1,
+	}, sums(t, reader, "logins"))
+}
+
+func TestMeter_CardinalityLimitDeltaReset(t *testing.T) {
+	t.Parallel()
+	reader := sdkMetric.NewManualReader(sdkMetric.WithTemporalitySelector(statsd.TemporalitySelector))
+	meter := otelmeter.NewMeter(&config.MetricsConfig{
+		CardinalityLimit: 2,
+		ExporterConfig:   config.MetricExporterConfig{Type: model.MetricExporterTypeStatsD},
+	}, sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)))
+
+	logins, err := meter.Counter("logins")
+	require.NoError(t, err)
+	logins.Add(context.Background(), 1, tracingModel.NewKeyValue("user.id", "0"))
+	assert.Equal(t, map[string]float64{"user.id=0": 1}, sums(t, reader, "logins"))
+
+	// the delta series are exported once, so the next collection starts with no attribute set
+	logins.Add(context.Background(), 1, tracingModel.NewKeyValue("user.id", "1"))
+	logins.Add(context.Background(), 1, tracingModel.NewKeyValue("user.id", "2"))
+	assert.Equal(t, map[string]float64{
+		"user.id=1":                 1,
+		"otel.metric.overflow=true": 1,
+	}, sums(t, reader, "logins"))
+}
+
+func TestMeter_CardinalityLimitObservable(t *testing.T) {
+	t.Parallel()
+	first, second := sdkMetric.NewManualReader(), sdkMetric.NewManualReader()
+	meter := otelmeter.NewMeter(&config.MetricsConfig{CardinalityLimit: 3},
+		sdkMetric.NewMeterProvider(sdkMetric.WithReader(first), sdkMetric.WithReader(second)))
+
+	// e.g. the connections of two pools, reported by their own callbacks
+	for _, pool := range []string{"main", "replica"} {
+		_, err := meter.ObservableUpDownCounter("connections", func(_ context.Context, o model.Observer) error {
+			o.Observe(1, tracingModel.NewKeyValue("pool.name", pool))
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	// the callbacks share the limit, and their attribute sets are kept until the next collection
+	want := map[string]float64{"pool.name=main": 1, "pool.name=replica": 1}
+	for range 2 {
+		assert.Equal(t, want, sums(t, first, "connections"))
+		assert.Equal(t, want, sums(t, second, "connections"))
+	}
+
+	_, err := meter.ObservableUpDownCounter("connections", func(_ context.Context, o model.Observer) error {
+		o.Observe(1, tracingModel.NewKeyValue("pool.name", "analytics"))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{
+		"pool.name=main":            1,
+		"pool.name=replica":         1,
+		"otel.metric.overflow=true": 1,
+	}, sums(t, first, "connections"))
+}
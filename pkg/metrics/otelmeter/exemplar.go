@@ -0,0 +1,29 @@
+package otelmeter
+
+import (
+	"fmt"
+
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+)
+
+//nolint:gochecknoglobals // lookup table
+var exemplarFilters = map[model.ExemplarFilter]exemplar.Filter{
+	model.ExemplarFilterTraceBased: exemplar.TraceBasedFilter,
+	model.ExemplarFilterAlwaysOn:   exemplar.AlwaysOnFilter,
+	model.ExemplarFilterAlwaysOff:  exemplar.AlwaysOffFilter,
+}
+
+// exemplarFilterOptions returns the provider options selecting the measurements kept as exemplars,
+// none for ExemplarFilterDefault so that the SDK keeps its own filter.
+func exemplarFilterOptions(filter model.ExemplarFilter) ([]sdkMetric.Option, error) {
+	if filter == model.ExemplarFilterDefault {
+		return nil, nil
+	}
+	f, ok := exemplarFilters[filter]
+	if !ok {
+		return nil, fmt.Errorf("failed to set exemplar filter: unknown filter %s", filter)
+	}
+	return []sdkMetric.Option{sdkMetric.WithExemplarFilter(f)}, nil
+}
@@ -0,0 +1,153 @@
+package otelmeter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nash-567/goObserve/internal/tlsconfig"
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/metrics/statsd"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+var ErrUnknownMetricExporterType = fmt.Errorf("unknown metric exporter type")
+
+// NewMetricExporter creates a new metric exporter based on the provided configuration.
+// stdout exporter writes the metrics to the stdout at every export interval.
+// http exporter exports the metrics to the specified endpoint using OTLP over HTTP.
+// grpc exporter exports the metrics to the specified endpoint using OTLP over gRPC.
+// statsd exporter sends the metrics to the StatsD agent at the specified host:port address over UDP.
+//
+//nolint:ireturn // the exporter type depends on the configuration
+func NewMetricExporter(ctx context.Context, cfg *config.MetricsConfig) (sdkMetric.Exporter, error) {
+	var (
+		exporter sdkMetric.Exporter
+		err      error
+	)
+
+	switch cfg.ExporterConfig.Type {
+	case model.MetricExporterTypeStdout:
+		exporter, err = stdoutmetric.New(stdoutmetric.WithPrettyPrint())
+	case model.MetricExporterTypeHTTP:
+		exporter, err = newOTLPMetricHTTPExporter(ctx, &cfg.ExporterConfig)
+	case model.MetricExporterTypeGRPC:
+		exporter, err = newOTLPMetricGRPCExporter(ctx, &cfg.ExporterConfig)
+	case model.MetricExporterTypeStatsD:
+		exporter, err = newStatsDExporter(&cfg.ExporterConfig)
+	default:
+		err = ErrUnknownMetricExporterType
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+func newOTLPMetricHTTPExporter(ctx context.Context, cfg *config.MetricExporterConfig) (*otlpmetrichttp.Exporter, error) {
+	opts, err := otlpMetricHTTPOptions(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlpmetrichttp exporter: %w", err)
+	}
+	exporter, err := otlpmetrichttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlpmetrichttp exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+func otlpMetricHTTPOptions(cfg *config.MetricExporterConfig) ([]otlpmetrichttp.Option, error) {
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{
+			Enabled:         cfg.RetryConfig.Enabled,
+			InitialInterval: cfg.RetryConfig.InitialInterval,
+			MaxInterval:     cfg.RetryConfig.MaxInterval,
+			MaxElapsedTime:  cfg.RetryConfig.MaxElapsedTime,
+		}),
+		otlpmetrichttp.WithTimeout(cfg.Timeout),
+		otlpmetrichttp.WithEndpointURL(cfg.EndpointURL),
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == model.CompressionTypeGzip {
+		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	}
+
+	// WithEndpointURL derives the transport security from the URL scheme, so the
+	// explicit settings must come after it to take precedence.
+	if cfg.Insecure {
+		return append(opts, otlpmetrichttp.WithInsecure()), nil
+	}
+	tlsCfg, err := tlsconfig.New(&cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsCfg))
+	}
+	return opts, nil
+}
+
+func newOTLPMetricGRPCExporter(ctx context.Context, cfg *config.MetricExporterConfig) (*otlpmetricgrpc.Exporter, error) {
+	opts, err := otlpMetricGRPCOptions(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlpmetricgrpc exporter: %w", err)
+	}
+	exporter, err := otlpmetricgrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlpmetricgrpc exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+func otlpMetricGRPCOptions(cfg *config.MetricExporterConfig) ([]otlpmetricgrpc.Option, error) {
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig{
+			Enabled:         cfg.RetryConfig.Enabled,
+			InitialInterval: cfg.RetryConfig.InitialInterval,
+			MaxInterval:     cfg.RetryConfig.MaxInterval,
+			MaxElapsedTime:  cfg.RetryConfig.MaxElapsedTime,
+		}),
+		otlpmetricgrpc.WithTimeout(cfg.Timeout),
+		otlpmetricgrpc.WithEndpointURL(cfg.EndpointURL),
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == model.CompressionTypeGzip {
+		opts = append(opts, otlpmetricgrpc.WithCompressor(gzip.Name))
+	}
+
+	// WithEndpointURL derives the transport security from the URL scheme, so the
+	// explicit settings must come after it to take precedence.
+	if cfg.Insecure {
+		return append(opts, otlpmetricgrpc.WithInsecure()), nil
+	}
+	tlsCfg, err := tlsconfig.New(&cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+	}
+	return opts, nil
+}
+
+func newStatsDExporter(cfg *config.MetricExporterConfig) (*statsd.Exporter, error) {
+	opts := []statsd.Option{statsd.WithPrefix(cfg.StatsD.Prefix)}
+	if cfg.StatsD.MaxPacketSize > 0 {
+		opts = append(opts, statsd.WithMaxPacketSize(cfg.StatsD.MaxPacketSize))
+	}
+	exporter, err := statsd.New(cfg.EndpointURL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create statsd exporter: %w", err)
+	}
+	return exporter, nil
+}
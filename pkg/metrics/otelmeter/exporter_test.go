@@ -0,0 +1,101 @@
+package otelmeter_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	collectorMetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/metrics/otelmeter"
+)
+
+// writeCA writes the certificate of the TLS server to a PEM file and returns its path.
+func writeCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(path, caPEM, 0o600))
+	return path
+}
+
+func TestNewMetricExporter_HTTPTransport(t *testing.T) {
+	t.Parallel()
+	received := make(chan *collectorMetrics.ExportMetricsServiceRequest, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/metrics", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		body, err := gzip.NewReader(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		payload, err := io.ReadAll(body)
+		assert.NoError(t, err)
+		req := &collectorMetrics.ExportMetricsServiceRequest{}
+		assert.NoError(t, proto.Unmarshal(payload, req))
+		received <- req
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer server.Close()
+
+	exporter, err := otelmeter.NewMetricExporter(context.Background(), &config.MetricsConfig{
+		ExporterConfig: config.MetricExporterConfig{
+			Type:        model.MetricExporterTypeHTTP,
+			EndpointURL: server.URL + "/v1/metrics",
+			Headers:     map[string]string{"Authorization": "Bearer token"},
+			Compression: model.CompressionTypeGzip,
+			TLS:         config.MetricExporterTLSConfig{CAFile: writeCA(t, server)},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = exporter.Shutdown(context.Background()) })
+
+	require.NoError(t, exporter.Export(context.Background(), &metricdata.ResourceMetrics{
+		ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{{
+			Name: "jobs",
+			Data: metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{{Value: 7}}},
+		}}}},
+	}))
+	req := <-received
+	require.Len(t, req.GetResourceMetrics(), 1)
+	assert.Equal(t, "jobs", req.GetResourceMetrics()[0].GetScopeMetrics()[0].GetMetrics()[0].GetName())
+}
+
+func TestNewMetricExporter_InvalidCAFile(t *testing.T) {
+	t.Parallel()
+	invalid := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a certificate"), 0o600))
+
+	for _, exporterType := range []model.MetricExporterType{model.MetricExporterTypeHTTP, model.MetricExporterTypeGRPC} {
+		_, err := otelmeter.NewMetricExporter(context.Background(), &config.MetricsConfig{
+			ExporterConfig: config.MetricExporterConfig{
+				Type:        exporterType,
+				EndpointURL: "https://localhost:4318",
+				TLS:         config.MetricExporterTLSConfig{CAFile: invalid},
+			},
+		})
+		require.ErrorContains(t, err, "no valid certificates found in CA file", exporterType.String())
+
+		_, err = otelmeter.NewMetricExporter(context.Background(), &config.MetricsConfig{
+			ExporterConfig: config.MetricExporterConfig{
+				Type:        exporterType,
+				EndpointURL: "https://localhost:4318",
+				TLS:         config.MetricExporterTLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+			},
+		})
+		require.ErrorIs(t, err, os.ErrNotExist, exporterType.String())
+	}
+}
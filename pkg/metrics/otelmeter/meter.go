@@ -0,0 +1,165 @@
+package otelmeter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// Meter is a wrapper around the OpenTelemetry meter that implements the model.Meter interface.
+type Meter struct {
+	meterProvider   metric.MeterProvider
+	sdkMeter        metric.Meter
+	limiters        *cardinalityLimiters
+	callbackTimeout time.Duration
+}
+
+// NewMeter creates a new meter instance which is used across the application.
+// The instruments it creates are bounded by the cardinality limits of cfg.
+func NewMeter(cfg *config.MetricsConfig, mp metric.MeterProvider) *Meter {
+	m := &Meter{
+		meterProvider: mp,
+		sdkMeter: mp.Meter(
+			cfg.InstrumentationLibrary.Name,
+			metric.WithInstrumentationVersion(cfg.InstrumentationLibrary.Version),
+			metric.WithSchemaURL(cfg.InstrumentationLibrary.SchemaURL),
+		),
+		limiters:        newCardinalityLimiters(cfg),
+		callbackTimeout: cfg.CallbackTimeout,
+	}
+	if m.callbackTimeout <= 0 {
+		m.callbackTimeout = defaultCallbackTimeout
+	}
+	if cfg.CardinalityLimit > 0 || len(cfg.CardinalityLimits) > 0 {
+		// the callback of the overflows also resets the limiters at every collection
+		_, err := m.sdkMeter.Float64ObservableCounter(metricOverflows,
+			metric.WithUnit("{measurement}"),
+			metric.WithDescription("The number of measurements recorded in the overflow series of an instrument past its cardinality limit."),
+			metric.WithFloat64Callback(m.limiters.observe))
+		if err != nil {
+			// the overflows are still logged
+			otel.Handle(fmt.Errorf("failed to create counter %s: %w", metricOverflows, err))
+		}
+	}
+	return m
+}
+
+// MeterProvider returns the meter provider.
+func (m *Meter) MeterProvider() metric.MeterProvider {
+	return m.meterProvider
+}
+
+// Counter creates a monotonic counter.
+//
+//nolint:ireturn // implements model.Meter interface
+func (m *Meter) Counter(name string, opts ...model.InstrumentOption) (model.Counter, error) {
+	cfg := model.NewInstrumentConfig(opts...)
+	c, err := m.sdkMeter.Float64Counter(name,
+		metric.WithDescription(cfg.Description()),
+		metric.WithUnit(cfg.Unit()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create counter %s: %w", name, err)
+	}
+	return &counter{sdkCounter: c, limiter: m.limiters.forSync(name, sdkMetric.InstrumentKindCounter)}, nil
+}
+
+// UpDownCounter creates a counter that can also decrease.
+//
+//nolint:ireturn // implements model.Meter interface
+func (m *Meter) UpDownCounter(name string, opts ...model.InstrumentOption) (model.UpDownCounter, error) {
+	cfg := model.NewInstrumentConfig(opts...)
+	c, err := m.sdkMeter.Float64UpDownCounter(name,
+		metric.WithDescription(cfg.Description()),
+		metric.WithUnit(cfg.Unit()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create up-down counter %s: %w", name, err)
+	}
+	return &upDownCounter{sdkCounter: c, limiter: m.limiters.forSync(name, sdkMetric.InstrumentKindUpDownCounter)}, nil
+}
+
+// Histogram creates an instrument recording the distribution of values.
+//
+//nolint:ireturn // implements model.Meter interface
+func (m *Meter) Histogram(name string, opts ...model.InstrumentOption) (model.Histogram, error) {
+	cfg := model.NewInstrumentConfig(opts...)
+	histogramOpts := []metric.Float64HistogramOption{
+		metric.WithDescription(cfg.Description()),
+		metric.WithUnit(cfg.Unit()),
+	}
+	if len(cfg.BucketBoundaries()) > 0 {
+		histogramOpts = append(histogramOpts, metric.WithExplicitBucketBoundaries(cfg.BucketBoundaries()...))
+	}
+	h, err := m.sdkMeter.Float64Histogram(name, histogramOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create histogram %s: %w", name, err)
+	}
+	return &histogram{sdkHistogram: h, limiter: m.limiters.forSync(name, sdkMetric.InstrumentKindHistogram)}, nil
+}
+
+// Gauge creates an instrument recording the current value of something.
+//
+//nolint:ireturn // implements model.Meter interface
+func (m *Meter) Gauge(name string, opts ...model.InstrumentOption) (model.Gauge, error) {
+	cfg := model.NewInstrumentConfig(opts...)
+	g, err := m.sdkMeter.Float64Gauge(name,
+		metric.WithDescription(cfg.Description()),
+		metric.WithUnit(cfg.Unit()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gauge %s: %w", name, err)
+	}
+	return &gauge{sdkGauge: g, limiter: m.limiters.forSync(name, sdkMetric.InstrumentKindGauge)}, nil
+}
+
+type counter struct {
+	sdkCounter metric.Float64Counter
+	limiter    *cardinalityLimiter
+}
+
+func (c *counter) Add(ctx context.Context, incr float64, attributes ...model.KeyValue) {
+	c.sdkCounter.Add(ctx, incr, metric.WithAttributeSet(c.limiter.apply(ctx, toAttributes(attributes))))
+}
+
+type upDownCounter struct {
+	sdkCounter metric.Float64UpDownCounter
+	limiter    *cardinalityLimiter
+}
+
+func (c *upDownCounter) Add(ctx context.Context, value float64, attributes ...model.KeyValue) {
+	c.sdkCounter.Add(ctx, value, metric.WithAttributeSet(c.limiter.apply(ctx, toAttributes(attributes))))
+}
+
+type histogram struct {
+	sdkHistogram metric.Float64Histogram
+	limiter      *cardinalityLimiter
+}
+
+func (h *histogram) Record(ctx context.Context, value float64, attributes ...model.KeyValue) {
+	h.sdkHistogram.Record(ctx, value, metric.WithAttributeSet(h.limiter.apply(ctx, toAttributes(attributes))))
+}
+
+type gauge struct {
+	sdkGauge metric.Float64Gauge
+	limiter  *cardinalityLimiter
+}
+
+func (g *gauge) Record(ctx context.Context, value float64, attributes ...model.KeyValue) {
+	g.sdkGauge.Record(ctx, value, metric.WithAttributeSet(g.limiter.apply(ctx, toAttributes(attributes))))
+}
+
+func toAttributes(attributes []model.KeyValue) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, len(attributes))
+	for i, v := range attributes {
+		attrs[i] = v.GetAttributeKeyValue()
+	}
+	return attrs
+}
@@ -0,0 +1,161 @@
+package otelmeter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"go.opentelemetry.io/otel/metric"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const defaultCallbackTimeout = time.Second
+
+var (
+	ErrCallbackTimeout = errors.New("observable instrument callback timed out")
+	ErrCallbackPanic   = errors.New("observable instrument callback panicked")
+)
+
+// ObservableCounter creates a monotonic counter reported by callback at every collection.
+//
+//nolint:ireturn // implements model.Meter interface
+func (m *Meter) ObservableCounter(name string, callback model.Callback, opts ...model.InstrumentOption) (model.Registration, error) {
+	cfg := model.NewInstrumentConfig(opts...)
+	c, err := m.sdkMeter.Float64ObservableCounter(name,
+		metric.WithDescription(cfg.Description()),
+		metric.WithUnit(cfg.Unit()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create observable counter %s: %w", name, err)
+	}
+	return m.registerCallback(name, sdkMetric.InstrumentKindObservableCounter, c, callback)
+}
+
+// ObservableUpDownCounter creates a counter that can also decrease, reported by callback at every collection.
+//
+//nolint:ireturn // implements model.Meter interface
+func (m *Meter) ObservableUpDownCounter(name string, callback model.Callback, opts ...model.InstrumentOption) (model.Registration, error) {
+	cfg := model.NewInstrumentConfig(opts...)
+	c, err := m.sdkMeter.Float64ObservableUpDownCounter(name,
+		metric.WithDescription(cfg.Description()),
+		metric.WithUnit(cfg.Unit()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create observable up-down counter %s: %w", name, err)
+	}
+	return m.registerCallback(name, sdkMetric.InstrumentKindObservableUpDownCounter, c, callback)
+}
+
+// ObservableGauge creates an instrument whose current value is reported by callback at every collection.
+//
+//nolint:ireturn // implements model.Meter interface
+func (m *Meter) ObservableGauge(name string, callback model.Callback, opts ...model.InstrumentOption) (model.Registration, error) {
+	cfg := model.NewInstrumentConfig(opts...)
+	g, err := m.sdkMeter.Float64ObservableGauge(name,
+		metric.WithDescription(cfg.Description()),
+		metric.WithUnit(cfg.Unit()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create observable gauge %s: %w", name, err)
+	}
+	return m.registerCallback(name, sdkMetric.InstrumentKindObservableGauge, g, callback)
+}
+
+//nolint:ireturn // implements model.Meter interface
+func (m *Meter) registerCallback(
+	name string,
+	kind sdkMetric.InstrumentKind,
+	instrument metric.Float64Observable,
+	callback model.Callback,
+) (model.Registration, error) {
+	cb := &observableCallback{
+		name:       name,
+		instrument: instrument,
+		callback:   callback,
+		timeout:    m.callbackTimeout,
+		limiter:    m.limiters.forObservable(name, kind),
+	}
+	reg, err := m.sdkMeter.RegisterCallback(cb.observe, instrument)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register callback of %s: %w", name, err)
+	}
+	return &registration{sdkRegistration: reg}, nil
+}
+
+// observableCallback protects the collection from the callback of an instrument: it runs in its own
+// goroutine and is abandoned after the timeout, a panic is recovered and both are reported as errors.
+// Its observations are only reported when it returns in time, and it is skipped while an abandoned
+// call is still running, so a blocked callback cannot pile goroutines up.
+type observableCallback struct {
+	name       string
+	instrument metric.Float64Observable
+	callback   model.Callback
+	timeout    time.Duration
+	limiter    *cardinalityLimiter
+	running    atomic.Bool
+}
+
+type observation struct {
+	value      float64
+	attributes []model.KeyValue
+}
+
+// observations buffers the values observed by a callback until it returns.
+type observations []observation
+
+func (o *observations) Observe(value float64, attributes ...model.KeyValue) {
+	*o = append(*o, observation{value: value, attributes: attributes})
+}
+
+func (c *observableCallback) observe(ctx context.Context, o metric.Observer) error {
+	if !c.running.CompareAndSwap(false, true) {
+		return fmt.Errorf("%w: %s is still running since a previous collection", ErrCallbackTimeout, c.name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var observed observations
+	done := make(chan error, 1)
+	go func() {
+		err := c.call(ctx, &observed)
+		c.running.Store(false)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("callback of %s failed: %w", c.name, err)
+		}
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %s after %s", ErrCallbackTimeout, c.name, c.timeout)
+	}
+	for _, obs := range observed {
+		o.ObserveFloat64(c.instrument, obs.value,
+			metric.WithAttributeSet(c.limiter.apply(ctx, toAttributes(obs.attributes))))
+	}
+	return nil
+}
+
+func (c *observableCallback) call(ctx context.Context, observer model.Observer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrCallbackPanic, r)
+		}
+	}()
+	return c.callback(ctx, observer)
+}
+
+type registration struct {
+	sdkRegistration metric.Registration
+}
+
+func (r *registration) Unregister() error {
+	if err := r.sdkRegistration.Unregister(); err != nil {
+		return fmt.Errorf("failed to unregister callback: %w", err)
+	}
+	return nil
+}
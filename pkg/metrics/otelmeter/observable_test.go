@@ -0,0 +1,135 @@
+package otelmeter_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/metrics/otelmeter"
+	tracingModel "github.com/nash-567/goObserve/pkg/tracing/model"
+)
+
+// collect returns the value of the data points by metric name and the collection error.
+func collect(t *testing.T, reader *sdkMetric.ManualReader) (map[string]float64, error) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	values := map[string]float64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[float64]:
+				for _, dp := range data.DataPoints {
+					values[m.Name] += dp.Value
+				}
+			case metricdata.Gauge[float64]:
+				for _, dp := range data.DataPoints {
+					values[m.Name] += dp.Value
+				}
+			}
+		}
+	}
+	return values, err
+}
+
+func newObservableMeter(cfg *config.MetricsConfig) (*otelmeter.Meter, *sdkMetric.ManualReader) {
+	reader := sdkMetric.NewManualReader()
+	return otelmeter.NewMeter(cfg, sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader))), reader
+}
+
+func TestMeter_Observable(t *testing.T) {
+	t.Parallel()
+	meter, reader := newObservableMeter(&config.MetricsConfig{})
+
+	var bytesRead, depth float64
+	_, err := meter.ObservableCounter("reader.bytes", func(_ context.Context, o model.Observer) error {
+		o.Observe(bytesRead)
+		return nil
+	}, model.WithUnit("By"))
+	require.NoError(t, err)
+	_, err = meter.ObservableUpDownCounter("queue.depth", func(_ context.Context, o model.Observer) error {
+		o.Observe(depth, tracingModel.NewKeyValue("queue", "a"))
+		o.Observe(1, tracingModel.NewKeyValue("queue", "b"))
+		return nil
+	})
+	require.NoError(t, err)
+	cacheSize, err := meter.ObservableGauge("cache.size", func(_ context.Context, o model.Observer) error {
+		o.Observe(42)
+		return nil
+	})
+	require.NoError(t, err)
+
+	bytesRead, depth = 10, 3
+	values, err := collect(t, reader)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"reader.bytes": 10, "queue.depth": 4, "cache.size": 42}, values)
+
+	bytesRead, depth = 25, 0
+	require.NoError(t, cacheSize.Unregister())
+	values, err = collect(t, reader)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"reader.bytes": 25, "queue.depth": 1}, values)
+}
+
+func TestMeter_ObservablePanic(t *testing.T) {
+	t.Parallel()
+	meter, reader := newObservableMeter(&config.MetricsConfig{})
+
+	_, err := meter.ObservableGauge("broken", func(_ context.Context, o model.Observer) error {
+		o.Observe(1)
+		panic("boom")
+	})
+	require.NoError(t, err)
+	_, err = meter.ObservableGauge("healthy", func(_ context.Context, o model.Observer) error {
+		o.Observe(2)
+		return nil
+	})
+	require.NoError(t, err)
+
+	values, err := collect(t, reader)
+	// the SDK reports the callback errors as text
+	require.ErrorContains(t, err, otelmeter.ErrCallbackPanic.Error()+": boom")
+	assert.Equal(t, map[string]float64{"healthy": 2}, values)
+}
+
+func TestMeter_ObservableTimeout(t *testing.T) {
+	t.Parallel()
+	meter, reader := newObservableMeter(&config.MetricsConfig{CallbackTimeout: 20 * time.Millisecond})
+
+	release := make(chan struct{})
+	var blocked atomic.Bool
+	blocked.Store(true)
+	_, err := meter.ObservableGauge("slow", func(_ context.Context, o model.Observer) error {
+		if blocked.Load() {
+			// ignores the context, like a stuck call
+			<-release
+		}
+		o.Observe(7)
+		return nil
+	})
+	require.NoError(t, err)
+
+	values, err := collect(t, reader)
+	require.ErrorContains(t, err, otelmeter.ErrCallbackTimeout.Error())
+	assert.Empty(t, values)
+
+	// the blocked call is not started again
+	_, err = collect(t, reader)
+	require.ErrorContains(t, err, "still running")
+
+	blocked.Store(false)
+	close(release)
+	assert.Eventually(t, func() bool {
+		values, err = collect(t, reader)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]float64{"slow": 7}, values)
+}
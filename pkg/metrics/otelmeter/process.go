@@ -0,0 +1,119 @@
+package otelmeter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Semantic convention metric names of the process.
+// See https://opentelemetry.io/docs/specs/semconv/system/process-metrics/.
+const (
+	metricProcessCPUTime     = "process.cpu.time"
+	metricProcessMemoryUsage = "process.memory.usage"
+	metricProcessOpenFDs     = "process.open_file_descriptor.count"
+
+	attrCPUMode   = "cpu.mode"
+	cpuModeUser   = "user"
+	cpuModeSystem = "system"
+
+	procStatPath = "/proc/self/stat"
+	procFDPath   = "/proc/self/fd"
+	// clockTicks is USER_HZ, the unit of the CPU times in /proc, which is 100 on all the Linux architectures.
+	clockTicks = 100
+)
+
+var ErrMalformedProcStat = errors.New("malformed " + procStatPath)
+
+type processStats struct {
+	userCPU   float64 // in seconds
+	systemCPU float64 // in seconds
+	rss       int64   // in bytes
+	openFDs   int64
+}
+
+// registerProcessMetrics registers the process metrics read from /proc with meter.
+// Nothing is registered when /proc is not available, e.g. on other operating systems than Linux.
+func registerProcessMetrics(meter metric.Meter) error {
+	if _, err := os.Stat(procStatPath); err != nil {
+		return nil //nolint:nilerr // the process metrics are only available on Linux
+	}
+
+	cpuTime, err := meter.Float64ObservableCounter(metricProcessCPUTime,
+		metric.WithUnit("s"),
+		metric.WithDescription("Total CPU seconds broken down by different CPU modes."))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricProcessCPUTime, err)
+	}
+	memoryUsage, err := meter.Int64ObservableUpDownCounter(metricProcessMemoryUsage,
+		metric.WithUnit("By"),
+		metric.WithDescription("The amount of physical memory in use."))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricProcessMemoryUsage, err)
+	}
+	openFDs, err := meter.Int64ObservableUpDownCounter(metricProcessOpenFDs,
+		metric.WithUnit("{count}"),
+		metric.WithDescription("Number of file descriptors in use by the process."))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricProcessOpenFDs, err)
+	}
+
+	user := metric.WithAttributes(attribute.String(attrCPUMode, cpuModeUser))
+	system := metric.WithAttributes(attribute.String(attrCPUMode, cpuModeSystem))
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats, err := readProcessStats()
+		if err != nil {
+			return err
+		}
+		o.ObserveFloat64(cpuTime, stats.userCPU, user)
+		o.ObserveFloat64(cpuTime, stats.systemCPU, system)
+		o.ObserveInt64(memoryUsage, stats.rss)
+		o.ObserveInt64(openFDs, stats.openFDs)
+		return nil
+	}, cpuTime, memoryUsage, openFDs)
+	if err != nil {
+		return fmt.Errorf("failed to register the process metrics callback: %w", err)
+	}
+	return nil
+}
+
+func readProcessStats() (processStats, error) {
+	var stats processStats
+	data, err := os.ReadFile(procStatPath)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read %s: %w", procStatPath, err)
+	}
+	// the command name is in parentheses and may contain spaces, the fields after it start with
+	// the state, the 3rd field; utime, stime and rss are the 14th, 15th and 24th fields
+	end := strings.LastIndexByte(string(data), ')')
+	if end < 0 {
+		return stats, ErrMalformedProcStat
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 22 {
+		return stats, ErrMalformedProcStat
+	}
+	utime, errUser := strconv.ParseUint(fields[11], 10, 64)
+	stime, errSystem := strconv.ParseUint(fields[12], 10, 64)
+	rss, errRSS := strconv.ParseInt(fields[21], 10, 64)
+	if err = errors.Join(errUser, errSystem, errRSS); err != nil {
+		return stats, fmt.Errorf("%w: %w", ErrMalformedProcStat, err)
+	}
+	stats.userCPU = float64(utime) / clockTicks
+	stats.systemCPU = float64(stime) / clockTicks
+	stats.rss = rss * int64(os.Getpagesize())
+
+	entries, err := os.ReadDir(procFDPath)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read %s: %w", procFDPath, err)
+	}
+	// the directory itself is open while it is listed
+	stats.openFDs = int64(len(entries)) - 1
+	return stats, nil
+}
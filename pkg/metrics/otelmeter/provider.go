@@ -0,0 +1,121 @@
+package otelmeter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+)
+
+// ProviderOption configures optional behaviour of the meter provider created by NewMeterProvider.
+type ProviderOption func(*providerOptions)
+
+type providerOptions struct {
+	readers []sdkMetric.Reader
+}
+
+// WithReader registers readers collecting the metrics next to the exporter, e.g. the
+// sdkMetric.ManualReader served by the prometheus handler or the reader of a test.
+// They get the same views, resource, runtime metrics and exemplars as the exporter. The runtime
+// histograms are produced per reader, register NewRuntimeProducer with sdkMetric.WithProducer when
+// creating the reader to collect them too.
+func WithReader(readers ...sdkMetric.Reader) ProviderOption {
+	return func(o *providerOptions) {
+		o.readers = append(o.readers, readers...)
+	}
+}
+
+// NewMeterProvider creates a new meter provider exporting the metrics with the exporter provided,
+// every ExporterConfig.Interval. The exporter can be nil when the metrics are only collected by the
+// readers registered with WithReader. This provider is used to initialize the meter which is then used
+// across the application. The runtime and process metrics are registered with the meter of the
+// instrumentation library when RuntimeMetrics is enabled. An invalid view fails with ErrInvalidView.
+//
+//nolint:ireturn
+func NewMeterProvider(
+	cfg *config.MetricsConfig,
+	exporter sdkMetric.Exporter,
+	serviceName string,
+	opts ...ProviderOption,
+) (metric.MeterProvider, error) {
+	var options providerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if !cfg.Enabled {
+		return noop.NewMeterProvider(), nil
+	}
+	r, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(
+			semconv.ServiceName(serviceName),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating resource info: %w", err)
+	}
+
+	views, err := newViews(cfg.Views)
+	if err != nil {
+		return nil, err
+	}
+	exemplarOpts, err := exemplarFilterOptions(cfg.ExemplarFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	providerOpts := []sdkMetric.Option{
+		sdkMetric.WithResource(r),
+		sdkMetric.WithView(views...),
+	}
+	if exporter != nil {
+		readerOpts := []sdkMetric.PeriodicReaderOption{sdkMetric.WithInterval(cfg.ExporterConfig.Interval)}
+		if cfg.RuntimeMetrics.Enabled {
+			readerOpts = append(readerOpts, sdkMetric.WithProducer(NewRuntimeProducer(cfg)))
+		}
+		providerOpts = append(providerOpts, sdkMetric.WithReader(sdkMetric.NewPeriodicReader(exporter, readerOpts...)))
+	}
+	for _, reader := range options.readers {
+		providerOpts = append(providerOpts, sdkMetric.WithReader(reader))
+	}
+	mp := sdkMetric.NewMeterProvider(append(providerOpts, exemplarOpts...)...)
+	if cfg.RuntimeMetrics.Enabled {
+		if err = registerRuntimeAndProcessMetrics(cfg, mp); err != nil {
+			_ = mp.Shutdown(context.Background())
+			return nil, err
+		}
+	}
+	return mp, nil
+}
+
+// NewRuntimeProducer returns the producer of the go.schedule.duration and go.gc.pause.duration
+// histograms, read from runtime/metrics with the bucket bounds and counts of the runtime. NewMeterProvider
+// registers it with the periodic reader of the exporter when RuntimeMetrics is enabled.
+//
+//nolint:ireturn // the producer type is internal
+func NewRuntimeProducer(cfg *config.MetricsConfig) sdkMetric.Producer {
+	return newRuntimeHistogramProducer(instrumentation.Scope{
+		Name:      cfg.InstrumentationLibrary.Name,
+		Version:   cfg.InstrumentationLibrary.Version,
+		SchemaURL: cfg.InstrumentationLibrary.SchemaURL,
+	})
+}
+
+func registerRuntimeAndProcessMetrics(cfg *config.MetricsConfig, mp metric.MeterProvider) error {
+	meter := mp.Meter(
+		cfg.InstrumentationLibrary.Name,
+		metric.WithInstrumentationVersion(cfg.InstrumentationLibrary.Version),
+		metric.WithSchemaURL(cfg.InstrumentationLibrary.SchemaURL),
+	)
+	if err := registerRuntimeMetrics(meter); err != nil {
+		return err
+	}
+	return registerProcessMetrics(meter)
+}
@@ -0,0 +1,354 @@
+package otelmeter_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	colMetricPb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/metrics/otelmeter"
+	tracingConfig "github.com/nash-567/goObserve/pkg/tracing/config"
+	tracingModel "github.com/nash-567/goObserve/pkg/tracing/model"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+func TestNewMetricExporter_UnknownType(t *testing.T) {
+	t.Parallel()
+	_, err := otelmeter.NewMetricExporter(context.Background(), &config.MetricsConfig{
+		ExporterConfig: config.MetricExporterConfig{Type: model.MetricExporterType(-1)},
+	})
+	require.ErrorIs(t, err, otelmeter.ErrUnknownMetricExporterType)
+}
+
+func TestNewMeterProvider_Disabled(t *testing.T) {
+	t.Parallel()
+	mp, err := otelmeter.NewMeterProvider(&config.MetricsConfig{}, nil, "test")
+	require.NoError(t, err)
+	assert.IsType(t, noop.MeterProvider{}, mp)
+}
+
+func TestNewMeterProvider_HTTP(t *testing.T) {
+	t.Parallel()
+	received := make(chan *colMetricPb.ExportMetricsServiceRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/metrics", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		req := &colMetricPb.ExportMetricsServiceRequest{}
+		assert.NoError(t, proto.Unmarshal(body, req))
+		received <- req
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.MetricsConfig{
+		Enabled: true,
+		ExporterConfig: config.MetricExporterConfig{
+			Type:        model.MetricExporterTypeHTTP,
+			EndpointURL: server.URL + "/v1/metrics",
+			Timeout:     time.Second,
+			Interval:    time.Hour,
+		},
+	}
+	exporter, err := otelmeter.NewMetricExporter(context.Background(), cfg)
+	require.NoError(t, err)
+	mp, err := otelmeter.NewMeterProvider(cfg, exporter, "checkout")
+	require.NoError(t, err)
+	sdkMP, ok := mp.(*sdkMetric.MeterProvider)
+	require.True(t, ok)
+	t.Cleanup(func() { _ = sdkMP.Shutdown(context.Background()) })
+
+	counter, err := otelmeter.NewMeter(cfg, mp).Counter("orders.created")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 3)
+	require.NoError(t, sdkMP.ForceFlush(context.Background()))
+
+	req := <-received
+	require.Len(t, req.GetResourceMetrics(), 1)
+	rm := req.GetResourceMetrics()[0]
+	var serviceName string
+	for _, attr := range rm.GetResource().GetAttributes() {
+		if attr.GetKey() == "service.name" {
+			serviceName = attr.GetValue().GetStringValue()
+		}
+	}
+	assert.Equal(t, "checkout", serviceName)
+	require.Len(t, rm.GetScopeMetrics(), 1)
+	require.Len(t, rm.GetScopeMetrics()[0].GetMetrics(), 1)
+	metric := rm.GetScopeMetrics()[0].GetMetrics()[0]
+	assert.Equal(t, "orders.created", metric.GetName())
+	assert.InDelta(t, 3, metric.GetSum().GetDataPoints()[0].GetAsDouble(), 0)
+}
+
+// recordingExporter keeps the names of the exported metrics.
+type recordingExporter struct {
+	mu    sync.Mutex
+	names map[string]metricdata.Aggregation
+}
+
+func (e *recordingExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			e.names[m.Name] = m.Data
+		}
+	}
+	return nil
+}
+
+func (e *recordingExporter) Temporality(kind sdkMetric.InstrumentKind) metricdata.Temporality {
+	return sdkMetric.DefaultTemporalitySelector(kind)
+}
+
+func (e *recordingExporter) Aggregation(kind sdkMetric.InstrumentKind) sdkMetric.Aggregation {
+	return sdkMetric.DefaultAggregationSelector(kind)
+}
+
+func (e *recordingExporter) ForceFlush(context.Context) error { return nil }
+
+func (e *recordingExporter) Shutdown(context.Context) error { return nil }
+
+func TestNewMeterProvider_RuntimeMetrics(t *testing.T) {
+	t.Parallel()
+	exporter := &recordingExporter{names: map[string]metricdata.Aggregation{}}
+	cfg := &config.MetricsConfig{
+		Enabled:        true,
+		ExporterConfig: config.MetricExporterConfig{Interval: time.Hour},
+		RuntimeMetrics: config.RuntimeMetricsConfig{Enabled: true},
+	}
+	mp, err := otelmeter.NewMeterProvider(cfg, exporter, "checkout")
+	require.NoError(t, err)
+	sdkMP, ok := mp.(*sdkMetric.MeterProvider)
+	require.True(t, ok)
+	t.Cleanup(func() { _ = sdkMP.Shutdown(context.Background()) })
+	require.NoError(t, sdkMP.ForceFlush(context.Background()))
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	for _, name := range []string{
+		"go.memory.used", "go.memory.allocated", "go.memory.allocations", "go.memory.gc.goal",
+		"go.goroutine.count", "go.processor.limit", "go.config.gogc",
+	} {
+		assert.Contains(t, exporter.names, name)
+	}
+	// the runtime histograms keep the bounds and counts of runtime/metrics
+	for _, name := range []string{"go.schedule.duration", "go.gc.pause.duration"} {
+		hist, ok := exporter.names[name].(metricdata.Histogram[float64])
+		require.True(t, ok, name)
+		require.Len(t, hist.DataPoints, 1, name)
+		dp := hist.DataPoints[0]
+		assert.Len(t, dp.BucketCounts, len(dp.Bounds)+1, name)
+		assert.Greater(t, len(dp.Bounds), 100, name)
+		var count uint64
+		for _, c := range dp.BucketCounts {
+			count += c
+		}
+		assert.Equal(t, dp.Count, count, name)
+	}
+	assert.Positive(t, exporter.names["go.schedule.duration"].(metricdata.Histogram[float64]).DataPoints[0].Count)
+	goroutines, ok := exporter.names["go.goroutine.count"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, goroutines.DataPoints, 1)
+	assert.Positive(t, goroutines.DataPoints[0].Value)
+	memoryUsed, ok := exporter.names["go.memory.used"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	assert.Len(t, memoryUsed.DataPoints, 2)
+
+	if _, err = os.Stat("/proc/self/stat"); err != nil {
+		return
+	}
+	for _, name := range []string{"process.cpu.time", "process.memory.usage", "process.open_file_descriptor.count"} {
+		assert.Contains(t, exporter.names, name)
+	}
+	rss, ok := exporter.names["process.memory.usage"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	assert.Positive(t, rss.DataPoints[0].Value)
+}
+
+func TestNewMeterProvider_Views(t *testing.T) {
+	t.Parallel()
+	exporter := &recordingExporter{names: map[string]metricdata.Aggregation{}}
+	cfg := &config.MetricsConfig{
+		Enabled:        true,
+		ExporterConfig: config.MetricExporterConfig{Interval: time.Hour},
+		Views: []config.ViewConfig{
+			{
+				InstrumentName: "cache.duration",
+				Rename:         "cache.latency",
+				Aggregation: config.AggregationConfig{
+					Type:             model.AggregationTypeExplicitBucketHistogram,
+					BucketBoundaries: []float64{0.01, 0.1, 1},
+				},
+			},
+			{
+				InstrumentName:    "batch.*",
+				Aggregation:       config.AggregationConfig{Type: model.AggregationTypeBase2ExponentialHistogram, MaxSize: 20, MaxScale: int32Ptr(0)},
+				AttributeDenyList: []string{"job.id"},
+			},
+			{
+				InstrumentName: "debug.*",
+				Aggregation:    config.AggregationConfig{Type: model.AggregationTypeDrop},
+			},
+		},
+	}
+	mp, err := otelmeter.NewMeterProvider(cfg, exporter, "checkout")
+	require.NoError(t, err)
+	sdkMP, ok := mp.(*sdkMetric.MeterProvider)
+	require.True(t, ok)
+	t.Cleanup(func() { _ = sdkMP.Shutdown(context.Background()) })
+
+	ctx := context.Background()
+	meter := otelmeter.NewMeter(cfg, mp)
+	cache, err := meter.Histogram("cache.duration", model.WithUnit("ms"))
+	require.NoError(t, err)
+	cache.Record(ctx, 0.05)
+	batch, err := meter.Histogram("batch.duration", model.WithUnit("s"))
+	require.NoError(t, err)
+	batch.Record(ctx, 90, tracingModel.NewKeyValue("job.id", "42"), tracingModel.NewKeyValue("job.name", "export"))
+	batch.Record(ctx, 300, tracingModel.NewKeyValue("job.id", "43"), tracingModel.NewKeyValue("job.name", "export"))
+	debug, err := meter.Counter("debug.calls")
+	require.NoError(t, err)
+	debug.Add(ctx, 1)
+	require.NoError(t, sdkMP.ForceFlush(ctx))
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	assert.NotContains(t, exporter.names, "cache.duration")
+	assert.NotContains(t, exporter.names, "debug.calls")
+
+	latency, ok := exporter.names["cache.latency"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	assert.Equal(t, []float64{0.01, 0.1, 1}, latency.DataPoints[0].Bounds)
+	assert.Equal(t, []uint64{0, 1, 0, 0}, latency.DataPoints[0].BucketCounts)
+
+	batches, ok := exporter.names["batch.duration"].(metricdata.ExponentialHistogram[float64])
+	require.True(t, ok)
+	require.Len(t, batches.DataPoints, 1)
+	assert.Equal(t, uint64(2), batches.DataPoints[0].Count)
+	// a zero MaxScale is kept, the buckets are powers of 2
+	assert.Equal(t, int32(0), batches.DataPoints[0].Scale)
+	assert.Equal(t, 1, batches.DataPoints[0].Attributes.Len())
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
+
+func TestNewMeterProvider_WithReader(t *testing.T) {
+	t.Parallel()
+	reader := sdkMetric.NewManualReader()
+	cfg := &config.MetricsConfig{
+		Enabled: true,
+		Views:   []config.ViewConfig{{InstrumentName: "cache.duration", Rename: "cache.latency"}},
+	}
+	// no exporter, the metrics are only collected by the reader
+	mp, err := otelmeter.NewMeterProvider(cfg, nil, "checkout", otelmeter.WithReader(reader))
+	require.NoError(t, err)
+	sdkMP, ok := mp.(*sdkMetric.MeterProvider)
+	require.True(t, ok)
+	t.Cleanup(func() { _ = sdkMP.Shutdown(context.Background()) })
+
+	cache, err := otelmeter.NewMeter(cfg, mp).Histogram("cache.duration")
+	require.NoError(t, err)
+	cache.Record(context.Background(), 0.05)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	serviceName, ok := rm.Resource.Set().Value("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "checkout", serviceName.AsString())
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, "cache.latency", rm.ScopeMetrics[0].Metrics[0].Name)
+}
+
+func TestNewMeterProvider_InvalidView(t *testing.T) {
+	t.Parallel()
+	for name, view := range map[string]config.ViewConfig{
+		"missing name":     {},
+		"wildcard rename":  {InstrumentName: "http.*", Rename: "requests"},
+		"allow and deny":   {InstrumentName: "a", AttributeAllowList: []string{"x"}, AttributeDenyList: []string{"y"}},
+		"missing buckets":  {InstrumentName: "a", Aggregation: config.AggregationConfig{Type: model.AggregationTypeExplicitBucketHistogram}},
+		"unsorted buckets": {InstrumentName: "a", Aggregation: config.AggregationConfig{Type: model.AggregationTypeExplicitBucketHistogram, BucketBoundaries: []float64{2, 1}}},
+		"max scale":        {InstrumentName: "a", Aggregation: config.AggregationConfig{Type: model.AggregationTypeBase2ExponentialHistogram, MaxScale: int32Ptr(21)}},
+	} {
+		_, err := otelmeter.NewMeterProvider(&config.MetricsConfig{
+			Enabled: true,
+			Views:   []config.ViewConfig{view},
+		}, &recordingExporter{}, "checkout")
+		require.ErrorIs(t, err, otelmeter.ErrInvalidView, name)
+	}
+}
+
+// exemplars records a latency in a span and outside of it, and returns the exported exemplars.
+func exemplars(t *testing.T, filter model.ExemplarFilter) ([]metricdata.Exemplar[float64], trace.TraceID) {
+	t.Helper()
+	exporter := &recordingExporter{names: map[string]metricdata.Aggregation{}}
+	cfg := &config.MetricsConfig{
+		Enabled:        true,
+		ExporterConfig: config.MetricExporterConfig{Interval: time.Hour},
+		ExemplarFilter: filter,
+	}
+	mp, err := otelmeter.NewMeterProvider(cfg, exporter, "checkout")
+	require.NoError(t, err)
+	sdkMP, ok := mp.(*sdkMetric.MeterProvider)
+	require.True(t, ok)
+	t.Cleanup(func() { _ = sdkMP.Shutdown(context.Background()) })
+	latency, err := otelmeter.NewMeter(cfg, mp).Histogram("latency", model.WithBucketBoundaries(10, 100))
+	require.NoError(t, err)
+
+	tracer := oteltracer.NewTracer(&tracingConfig.TracingConfig{}, sdkTrace.NewTracerProvider())
+	ctx, span := tracer.StartSpan(context.Background(), "checkout")
+	latency.Record(ctx, 50)
+	span.End()
+	latency.Record(context.Background(), 500)
+	require.NoError(t, sdkMP.ForceFlush(context.Background()))
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	histogram, ok := exporter.names["latency"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	return histogram.DataPoints[0].Exemplars, trace.SpanContextFromContext(ctx).TraceID()
+}
+
+func TestNewMeterProvider_Exemplars(t *testing.T) {
+	t.Parallel()
+	sampled, traceID := exemplars(t, model.ExemplarFilterTraceBased)
+	require.Len(t, sampled, 1)
+	assert.InDelta(t, 50, sampled[0].Value, 0)
+	assert.Equal(t, traceID[:], sampled[0].TraceID)
+	assert.Len(t, sampled[0].SpanID, 8)
+
+	all, _ := exemplars(t, model.ExemplarFilterAlwaysOn)
+	require.Len(t, all, 2)
+
+	none, _ := exemplars(t, model.ExemplarFilterAlwaysOff)
+	assert.Empty(t, none)
+
+	// the default filter of the SDK is trace based
+	def, _ := exemplars(t, model.ExemplarFilterDefault)
+	require.Len(t, def, 1)
+
+	_, err := otelmeter.NewMeterProvider(&config.MetricsConfig{
+		Enabled:        true,
+		ExemplarFilter: model.ExemplarFilter(-1),
+	}, &recordingExporter{}, "checkout")
+	require.Error(t, err)
+}
@@ -0,0 +1,252 @@
+package otelmeter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Semantic convention metric names of the Go runtime.
+// See https://opentelemetry.io/docs/specs/semconv/runtime/go-metrics/.
+const (
+	metricMemoryUsed        = "go.memory.used"
+	metricMemoryLimit       = "go.memory.limit"
+	metricMemoryAllocated   = "go.memory.allocated"
+	metricMemoryAllocations = "go.memory.allocations"
+	metricMemoryGCGoal      = "go.memory.gc.goal"
+	metricGoroutineCount    = "go.goroutine.count"
+	metricProcessorLimit    = "go.processor.limit"
+	metricConfigGOGC        = "go.config.gogc"
+	metricScheduleDuration  = "go.schedule.duration"
+	metricGCPauseDuration   = "go.gc.pause.duration" // not in the semantic conventions yet
+
+	attrMemoryType  = "go.memory.type"
+	memoryTypeStack = "stack"
+	memoryTypeOther = "other"
+)
+
+// Names of the runtime/metrics samples read at every collection.
+const (
+	sampleMemoryTotal   = "/memory/classes/total:bytes"
+	sampleHeapReleased  = "/memory/classes/heap/released:bytes"
+	sampleHeapStacks    = "/memory/classes/heap/stacks:bytes"
+	sampleOSStacks      = "/memory/classes/os-stacks:bytes"
+	sampleMemoryLimit   = "/gc/gomemlimit:bytes"
+	sampleAllocsBytes   = "/gc/heap/allocs:bytes"
+	sampleAllocsObjects = "/gc/heap/allocs:objects"
+	sampleHeapGoal      = "/gc/heap/goal:bytes"
+	sampleGoroutines    = "/sched/goroutines:goroutines"
+	sampleGOMAXPROCS    = "/sched/gomaxprocs:threads"
+	sampleGOGC          = "/gc/gogc:percent"
+
+	sampleSchedLatencies = "/sched/latencies:seconds"
+	sampleGCPauses       = "/gc/pauses:seconds"
+)
+
+type runtimeMetrics struct {
+	mu      sync.Mutex
+	samples []metrics.Sample
+	index   map[string]int
+
+	memoryUsed        metric.Int64ObservableUpDownCounter
+	memoryLimit       metric.Int64ObservableUpDownCounter
+	memoryAllocated   metric.Int64ObservableCounter
+	memoryAllocations metric.Int64ObservableCounter
+	memoryGCGoal      metric.Int64ObservableUpDownCounter
+	goroutineCount    metric.Int64ObservableUpDownCounter
+	processorLimit    metric.Int64ObservableUpDownCounter
+	configGOGC        metric.Int64ObservableUpDownCounter
+}
+
+// registerRuntimeMetrics registers the Go runtime metrics with meter. They are read from
+// runtime/metrics when the metrics are collected, so no goroutine is needed. The runtime
+// histograms are produced by runtimeHistogramProducer instead.
+func registerRuntimeMetrics(meter metric.Meter) error {
+	names := []string{
+		sampleMemoryTotal, sampleHeapReleased, sampleHeapStacks, sampleOSStacks, sampleMemoryLimit,
+		sampleAllocsBytes, sampleAllocsObjects, sampleHeapGoal, sampleGoroutines, sampleGOMAXPROCS,
+		sampleGOGC,
+	}
+	r := &runtimeMetrics{
+		samples: make([]metrics.Sample, len(names)),
+		index:   make(map[string]int, len(names)),
+	}
+	for i, name := range names {
+		r.samples[i].Name = name
+		r.index[name] = i
+	}
+
+	var err error
+	if r.memoryUsed, err = meter.Int64ObservableUpDownCounter(metricMemoryUsed,
+		metric.WithUnit("By"),
+		metric.WithDescription("Memory used by the Go runtime.")); err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricMemoryUsed, err)
+	}
+	if r.memoryLimit, err = meter.Int64ObservableUpDownCounter(metricMemoryLimit,
+		metric.WithUnit("By"),
+		metric.WithDescription("Go runtime memory limit configured by the user, if a limit exists.")); err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricMemoryLimit, err)
+	}
+	if r.memoryAllocated, err = meter.Int64ObservableCounter(metricMemoryAllocated,
+		metric.WithUnit("By"),
+		metric.WithDescription("Memory allocated to the heap by the application.")); err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricMemoryAllocated, err)
+	}
+	if r.memoryAllocations, err = meter.Int64ObservableCounter(metricMemoryAllocations,
+		metric.WithUnit("{allocation}"),
+		metric.WithDescription("Count of allocations to the heap by the application.")); err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricMemoryAllocations, err)
+	}
+	if r.memoryGCGoal, err = meter.Int64ObservableUpDownCounter(metricMemoryGCGoal,
+		metric.WithUnit("By"),
+		metric.WithDescription("Heap size target for the end of the GC cycle.")); err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricMemoryGCGoal, err)
+	}
+	if r.goroutineCount, err = meter.Int64ObservableUpDownCounter(metricGoroutineCount,
+		metric.WithUnit("{goroutine}"),
+		metric.WithDescription("Count of live goroutines.")); err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricGoroutineCount, err)
+	}
+	if r.processorLimit, err = meter.Int64ObservableUpDownCounter(metricProcessorLimit,
+		metric.WithUnit("{thread}"),
+		metric.WithDescription("The number of OS threads that can execute user-level Go code simultaneously.")); err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricProcessorLimit, err)
+	}
+	if r.configGOGC, err = meter.Int64ObservableUpDownCounter(metricConfigGOGC,
+		metric.WithUnit("%"),
+		metric.WithDescription("Heap size target percentage configured by the user, otherwise 100.")); err != nil {
+		return fmt.Errorf("failed to create %s: %w", metricConfigGOGC, err)
+	}
+
+	if _, err = meter.RegisterCallback(r.observe,
+		r.memoryUsed, r.memoryLimit, r.memoryAllocated, r.memoryAllocations, r.memoryGCGoal,
+		r.goroutineCount, r.processorLimit, r.configGOGC,
+	); err != nil {
+		return fmt.Errorf("failed to register the runtime metrics callback: %w", err)
+	}
+	return nil
+}
+
+func (r *runtimeMetrics) observe(_ context.Context, o metric.Observer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	metrics.Read(r.samples)
+
+	stacks := r.uint64(sampleHeapStacks) + r.uint64(sampleOSStacks)
+	used := r.uint64(sampleMemoryTotal) - r.uint64(sampleHeapReleased)
+	o.ObserveInt64(r.memoryUsed, toInt64(stacks),
+		metric.WithAttributes(attribute.String(attrMemoryType, memoryTypeStack)))
+	o.ObserveInt64(r.memoryUsed, toInt64(used-stacks),
+		metric.WithAttributes(attribute.String(attrMemoryType, memoryTypeOther)))
+	if limit := r.uint64(sampleMemoryLimit); limit != math.MaxInt64 {
+		o.ObserveInt64(r.memoryLimit, toInt64(limit))
+	}
+	o.ObserveInt64(r.memoryAllocated, toInt64(r.uint64(sampleAllocsBytes)))
+	o.ObserveInt64(r.memoryAllocations, toInt64(r.uint64(sampleAllocsObjects)))
+	o.ObserveInt64(r.memoryGCGoal, toInt64(r.uint64(sampleHeapGoal)))
+	o.ObserveInt64(r.goroutineCount, toInt64(r.uint64(sampleGoroutines)))
+	o.ObserveInt64(r.processorLimit, toInt64(r.uint64(sampleGOMAXPROCS)))
+	o.ObserveInt64(r.configGOGC, toInt64(r.uint64(sampleGOGC)))
+	return nil
+}
+
+func (r *runtimeMetrics) uint64(name string) uint64 {
+	v := r.samples[r.index[name]].Value
+	if v.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return v.Uint64()
+}
+
+func toInt64(v uint64) int64 {
+	if v > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(v)
+}
+
+// runtimeHistogramProducer produces the histograms of the Go runtime with their own bucket bounds
+// and counts, which the OTel API cannot record as they are since it only takes single measurements.
+// It is registered with the meter provider using sdkMetric.WithProducer.
+type runtimeHistogramProducer struct {
+	scope instrumentation.Scope
+	start time.Time
+
+	mu      sync.Mutex
+	samples []metrics.Sample
+}
+
+// runtimeHistograms describes the histograms produced, in the order of the samples.
+//
+//nolint:gochecknoglobals // lookup table
+var runtimeHistograms = []struct {
+	sample, name, description string
+}{
+	{sampleSchedLatencies, metricScheduleDuration,
+		"The time goroutines have spent in the scheduler in a runnable state before actually running."},
+	{sampleGCPauses, metricGCPauseDuration, "The time the application was stopped by the garbage collector."},
+}
+
+func newRuntimeHistogramProducer(scope instrumentation.Scope) *runtimeHistogramProducer {
+	p := &runtimeHistogramProducer{
+		scope:   scope,
+		start:   time.Now(),
+		samples: make([]metrics.Sample, len(runtimeHistograms)),
+	}
+	for i, h := range runtimeHistograms {
+		p.samples[i].Name = h.sample
+	}
+	return p
+}
+
+// Produce returns the cumulative histograms of the runtime. The runtime does not record the sum of
+// the values, it is the lower bound given by the buckets: the sum of their lower bounds times their count.
+func (p *runtimeHistogramProducer) Produce(context.Context) ([]metricdata.ScopeMetrics, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	metrics.Read(p.samples)
+
+	now := time.Now()
+	sm := metricdata.ScopeMetrics{Scope: p.scope}
+	for i, h := range runtimeHistograms {
+		if p.samples[i].Value.Kind() != metrics.KindFloat64Histogram {
+			continue
+		}
+		sm.Metrics = append(sm.Metrics, metricdata.Metrics{
+			Name:        h.name,
+			Description: h.description,
+			Unit:        "s",
+			Data: metricdata.Histogram[float64]{
+				Temporality: metricdata.CumulativeTemporality,
+				DataPoints:  []metricdata.HistogramDataPoint[float64]{histogramPoint(p.samples[i].Value.Float64Histogram(), p.start, now)},
+			},
+		})
+	}
+	return []metricdata.ScopeMetrics{sm}, nil
+}
+
+// histogramPoint converts the runtime histogram, whose buckets are delimited by Buckets and start with
+// -Inf and end with +Inf, into a data point whose bounds are the inner bucket boundaries.
+func histogramPoint(hist *metrics.Float64Histogram, start, now time.Time) metricdata.HistogramDataPoint[float64] {
+	dp := metricdata.HistogramDataPoint[float64]{
+		StartTime:    start,
+		Time:         now,
+		Bounds:       append([]float64(nil), hist.Buckets[1:len(hist.Buckets)-1]...),
+		BucketCounts: append([]uint64(nil), hist.Counts...),
+	}
+	for i, count := range hist.Counts {
+		dp.Count += count
+		if lower := hist.Buckets[i]; count > 0 && !math.IsInf(lower, 0) {
+			dp.Sum += lower * float64(count)
+		}
+	}
+	return dp
+}
@@ -0,0 +1,111 @@
+package otelmeter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const (
+	defaultExponentialMaxSize  = 160
+	defaultExponentialMaxScale = 20
+	minExponentialMaxScale     = -10
+)
+
+var ErrInvalidView = errors.New("invalid view")
+
+// newViews converts the configured views to SDK views.
+func newViews(cfgs []config.ViewConfig) ([]sdkMetric.View, error) {
+	views := make([]sdkMetric.View, len(cfgs))
+	for i := range cfgs {
+		view, err := newView(&cfgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create view %d: %w", i, err)
+		}
+		views[i] = view
+	}
+	return views, nil
+}
+
+func newView(cfg *config.ViewConfig) (sdkMetric.View, error) {
+	switch {
+	case cfg.InstrumentName == "":
+		return nil, fmt.Errorf("%w: InstrumentName is required", ErrInvalidView)
+	case cfg.Rename != "" && strings.ContainsAny(cfg.InstrumentName, "*?"):
+		return nil, fmt.Errorf("%w: %s cannot be renamed, it matches several instruments", ErrInvalidView, cfg.InstrumentName)
+	case len(cfg.AttributeAllowList) > 0 && len(cfg.AttributeDenyList) > 0:
+		return nil, fmt.Errorf("%w: AttributeAllowList and AttributeDenyList cannot be combined", ErrInvalidView)
+	}
+	aggregation, err := newAggregation(&cfg.Aggregation)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := sdkMetric.Stream{
+		Name:        cfg.Rename,
+		Description: cfg.Description,
+		Aggregation: aggregation,
+	}
+	switch {
+	case len(cfg.AttributeAllowList) > 0:
+		stream.AttributeFilter = attribute.NewAllowKeysFilter(toKeys(cfg.AttributeAllowList)...)
+	case len(cfg.AttributeDenyList) > 0:
+		stream.AttributeFilter = attribute.NewDenyKeysFilter(toKeys(cfg.AttributeDenyList)...)
+	}
+	return sdkMetric.NewView(
+		sdkMetric.Instrument{Name: cfg.InstrumentName, Scope: instrumentation.Scope{Name: cfg.MeterName}},
+		stream,
+	), nil
+}
+
+//nolint:ireturn // the aggregation type depends on the configuration
+func newAggregation(cfg *config.AggregationConfig) (sdkMetric.Aggregation, error) {
+	switch cfg.Type {
+	case model.AggregationTypeDefault:
+		return nil, nil //nolint:nilnil // keeps the default aggregation of the instrument
+	case model.AggregationTypeExplicitBucketHistogram:
+		if len(cfg.BucketBoundaries) == 0 {
+			return nil, fmt.Errorf("%w: BucketBoundaries are required", ErrInvalidView)
+		}
+		for i := 1; i < len(cfg.BucketBoundaries); i++ {
+			if cfg.BucketBoundaries[i] <= cfg.BucketBoundaries[i-1] {
+				return nil, fmt.Errorf("%w: BucketBoundaries must be increasing", ErrInvalidView)
+			}
+		}
+		return sdkMetric.AggregationExplicitBucketHistogram{Boundaries: cfg.BucketBoundaries}, nil
+	case model.AggregationTypeBase2ExponentialHistogram:
+		aggregation := sdkMetric.AggregationBase2ExponentialHistogram{
+			MaxSize:  cfg.MaxSize,
+			MaxScale: defaultExponentialMaxScale,
+		}
+		if aggregation.MaxSize == 0 {
+			aggregation.MaxSize = defaultExponentialMaxSize
+		}
+		if cfg.MaxScale != nil {
+			aggregation.MaxScale = *cfg.MaxScale
+		}
+		if aggregation.MaxSize < 0 || aggregation.MaxScale < minExponentialMaxScale || aggregation.MaxScale > defaultExponentialMaxScale {
+			return nil, fmt.Errorf("%w: MaxSize must be positive and MaxScale from %d to %d",
+				ErrInvalidView, minExponentialMaxScale, defaultExponentialMaxScale)
+		}
+		return aggregation, nil
+	case model.AggregationTypeDrop:
+		return sdkMetric.AggregationDrop{}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown aggregation type %s", ErrInvalidView, cfg.Type)
+	}
+}
+
+func toKeys(keys []string) []attribute.Key {
+	attrKeys := make([]attribute.Key, len(keys))
+	for i, key := range keys {
+		attrKeys[i] = attribute.Key(key)
+	}
+	return attrKeys
+}
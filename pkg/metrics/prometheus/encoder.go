@@ -0,0 +1,378 @@
+package prometheus
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type format int
+
+const (
+	formatText format = iota
+	formatOpenMetrics
+)
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+	typeInfo      = "info"
+
+	targetInfoName = "target"
+
+	// labels identifying the instrumentation scope of the series, as the OpenTelemetry Prometheus exporter does.
+	labelScopeName    = "otel_scope_name"
+	labelScopeVersion = "otel_scope_version"
+
+	// maxExemplarLabelsLength is the OpenMetrics limit of the exemplar label set, in characters.
+	maxExemplarLabelsLength = 128
+)
+
+// family is a metric family: the series of a metric name sharing the same metadata.
+type family struct {
+	name   string // without the counter _total and info _info suffixes
+	typ    string
+	help   string
+	unit   string
+	series []series
+}
+
+// series is the group of samples of a data point, e.g. the buckets, sum and count of a histogram.
+type series struct {
+	labels  string
+	samples []sample
+}
+
+type sample struct {
+	suffix   string
+	labels   string // rendered label pairs, without braces
+	value    string
+	exemplar string // rendered exemplar, without the leading " # ", OpenMetrics only
+}
+
+// encode writes rm in the Prometheus text or OpenMetrics format.
+func encode(w io.Writer, rm *metricdata.ResourceMetrics, f format) error {
+	families := map[string]*family{}
+	if rm.Resource != nil && rm.Resource.Len() > 0 {
+		families[targetInfoName] = &family{
+			name:   targetInfoName,
+			typ:    typeInfo,
+			help:   "Target metadata",
+			series: []series{{samples: []sample{{suffix: "_info", labels: renderLabels(rm.Resource.Attributes()), value: "1"}}}},
+		}
+	}
+	for _, sm := range rm.ScopeMetrics {
+		// an empty label is the same as a missing one for Prometheus, it is left out
+		var scope []attribute.KeyValue
+		if sm.Scope.Name != "" {
+			scope = append(scope, attribute.String(labelScopeName, sm.Scope.Name))
+		}
+		if sm.Scope.Version != "" {
+			scope = append(scope, attribute.String(labelScopeVersion, sm.Scope.Version))
+		}
+		for _, m := range sm.Metrics {
+			addMetric(families, m, scope)
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		writeFamily(&b, families[name], f)
+	}
+	if f == formatOpenMetrics {
+		b.WriteString("# EOF\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// addMetric adds the series of m to its family, with the scope labels added to the attributes of every data point.
+func addMetric(families map[string]*family, m metricdata.Metrics, scope []attribute.KeyValue) {
+	name := metricName(m.Name, m.Unit)
+	var (
+		typ    string
+		series []series
+	)
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		typ, series = sumSeries(data, scope)
+	case metricdata.Sum[float64]:
+		typ, series = sumSeries(data, scope)
+	case metricdata.Gauge[int64]:
+		typ, series = typeGauge, gaugeSeries(data.DataPoints, scope)
+	case metricdata.Gauge[float64]:
+		typ, series = typeGauge, gaugeSeries(data.DataPoints, scope)
+	case metricdata.Histogram[int64]:
+		typ, series = typeHistogram, histogramSeries(data.DataPoints, scope)
+	case metricdata.Histogram[float64]:
+		typ, series = typeHistogram, histogramSeries(data.DataPoints, scope)
+	default:
+		// exponential histograms and summaries have no equivalent in the text formats
+		otel.Handle(fmt.Errorf("%w: %s has aggregation %T", ErrUnsupportedAggregation, m.Name, m.Data))
+		return
+	}
+	if typ == typeCounter {
+		name = strings.TrimSuffix(name, "_total")
+	}
+
+	f, ok := families[name]
+	if !ok {
+		f = &family{name: name, typ: typ, help: m.Description, unit: unitSuffix(m.Unit)}
+		families[name] = f
+	} else if f.typ != typ {
+		otel.Handle(fmt.Errorf("%w: %s is both a %s and a %s", ErrConflictingMetric, name, f.typ, typ))
+		return
+	}
+	f.series = append(f.series, series...)
+}
+
+func sumSeries[N int64 | float64](sum metricdata.Sum[N], scope []attribute.KeyValue) (string, []series) {
+	if !sum.IsMonotonic {
+		return typeGauge, gaugeSeries(sum.DataPoints, scope)
+	}
+	s := make([]series, len(sum.DataPoints))
+	for i, dp := range sum.DataPoints {
+		labels := renderLabels(append(dp.Attributes.ToSlice(), scope...))
+		s[i] = series{labels: labels, samples: []sample{{
+			suffix:   "_total",
+			labels:   labels,
+			value:    formatValue(dp.Value),
+			exemplar: lastExemplar(dp.Exemplars),
+		}}}
+	}
+	return typeCounter, s
+}
+
+func gaugeSeries[N int64 | float64](points []metricdata.DataPoint[N], scope []attribute.KeyValue) []series {
+	s := make([]series, len(points))
+	for i, dp := range points {
+		labels := renderLabels(append(dp.Attributes.ToSlice(), scope...))
+		s[i] = series{labels: labels, samples: []sample{{labels: labels, value: formatValue(dp.Value)}}}
+	}
+	return s
+}
+
+func histogramSeries[N int64 | float64](points []metricdata.HistogramDataPoint[N], scope []attribute.KeyValue) []series {
+	s := make([]series, len(points))
+	for i, dp := range points {
+		labels := renderLabels(append(dp.Attributes.ToSlice(), scope...))
+		exemplars := bucketExemplars(dp.Bounds, dp.Exemplars)
+		samples := make([]sample, 0, len(dp.Bounds)+3)
+
+		var cumulative uint64
+		for j, bound := range dp.Bounds {
+			cumulative += dp.BucketCounts[j]
+			samples = append(samples, sample{
+				suffix:   "_bucket",
+				labels:   joinLabels(labels, `le="`+formatFloat(bound)+`"`),
+				value:    strconv.FormatUint(cumulative, 10),
+				exemplar: exemplars[j],
+			})
+		}
+		samples = append(samples,
+			sample{
+				suffix:   "_bucket",
+				labels:   joinLabels(labels, `le="+Inf"`),
+				value:    strconv.FormatUint(dp.Count, 10),
+				exemplar: exemplars[len(dp.Bounds)],
+			},
+			sample{suffix: "_sum", labels: labels, value: formatValue(dp.Sum)},
+			sample{suffix: "_count", labels: labels, value: strconv.FormatUint(dp.Count, 10)},
+		)
+		s[i] = series{labels: labels, samples: samples}
+	}
+	return s
+}
+
+// bucketExemplars returns the rendered exemplar of every bucket, the +Inf one included.
+// The latest exemplar falling in a bucket is used.
+func bucketExemplars[N int64 | float64](bounds []float64, exemplars []metricdata.Exemplar[N]) []string {
+	latest := make([]*metricdata.Exemplar[N], len(bounds)+1)
+	for i := range exemplars {
+		e := &exemplars[i]
+		bucket := sort.SearchFloat64s(bounds, float64(e.Value))
+		if latest[bucket] == nil || e.Time.After(latest[bucket].Time) {
+			latest[bucket] = e
+		}
+	}
+	rendered := make([]string, len(latest))
+	for i, e := range latest {
+		if e != nil {
+			rendered[i] = renderExemplar(*e)
+		}
+	}
+	return rendered
+}
+
+func lastExemplar[N int64 | float64](exemplars []metricdata.Exemplar[N]) string {
+	if len(exemplars) == 0 {
+		return ""
+	}
+	latest := exemplars[0]
+	for _, e := range exemplars[1:] {
+		if e.Time.After(latest.Time) {
+			latest = e
+		}
+	}
+	return renderExemplar(latest)
+}
+
+// renderExemplar renders the labels, value and timestamp of an exemplar. The trace and span IDs
+// are always included, the filtered attributes only while the label set fits in the OpenMetrics limit.
+func renderExemplar[N int64 | float64](e metricdata.Exemplar[N]) string {
+	var pairs []string
+	length := 0
+	add := func(name, value string) bool {
+		if length+len(name)+len(value) > maxExemplarLabelsLength {
+			return false
+		}
+		length += len(name) + len(value)
+		pairs = append(pairs, name+`="`+escapeLabelValue(value)+`"`)
+		return true
+	}
+	if len(e.TraceID) > 0 && len(e.SpanID) > 0 {
+		add("trace_id", hex.EncodeToString(e.TraceID))
+		add("span_id", hex.EncodeToString(e.SpanID))
+	}
+	for _, attr := range e.FilteredAttributes {
+		if !add(sanitizeLabel(string(attr.Key)), attr.Value.Emit()) {
+			break
+		}
+	}
+
+	exemplar := "{" + strings.Join(pairs, ",") + "} " + formatValue(e.Value)
+	if !e.Time.IsZero() {
+		exemplar += " " + formatTimestamp(e.Time)
+	}
+	return exemplar
+}
+
+func writeFamily(b *strings.Builder, f *family, format format) {
+	if len(f.series) == 0 {
+		return
+	}
+	sort.SliceStable(f.series, func(i, j int) bool { return f.series[i].labels < f.series[j].labels })
+
+	name, typ := f.name, f.typ
+	if format == formatText {
+		// the text format has no info type and names the counter families with their suffix
+		switch typ {
+		case typeCounter:
+			name += "_total"
+		case typeInfo:
+			name, typ = name+"_info", typeGauge
+		}
+	}
+	if f.help != "" {
+		fmt.Fprintf(b, "# HELP %s %s\n", name, escapeHelp(f.help))
+	}
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, typ)
+	if format == formatOpenMetrics && f.unit != "" && strings.HasSuffix(f.name, "_"+f.unit) {
+		fmt.Fprintf(b, "# UNIT %s %s\n", f.name, f.unit)
+	}
+
+	for _, s := range f.series {
+		for _, smp := range s.samples {
+			b.WriteString(f.name)
+			b.WriteString(smp.suffix)
+			if smp.labels != "" {
+				b.WriteString("{" + smp.labels + "}")
+			}
+			b.WriteString(" " + smp.value)
+			if format == formatOpenMetrics && smp.exemplar != "" {
+				b.WriteString(" # " + smp.exemplar)
+			}
+			b.WriteByte('\n')
+		}
+	}
+}
+
+// renderLabels renders the attributes as sorted label pairs. The values of the attributes
+// whose names collide once sanitized are joined with semicolons.
+func renderLabels(attrs []attribute.KeyValue) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	values := map[string][]string{}
+	for _, attr := range attrs {
+		name := sanitizeLabel(string(attr.Key))
+		values[name] = append(values[name], attr.Value.Emit())
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + escapeLabelValue(strings.Join(values[name], ";")) + `"`
+	}
+	return strings.Join(pairs, ",")
+}
+
+func joinLabels(labels, pair string) string {
+	if labels == "" {
+		return pair
+	}
+	return labels + "," + pair
+}
+
+//nolint:gochecknoglobals // stateless replacers
+var (
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+func formatValue[N int64 | float64](value N) string {
+	switch v := any(value).(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return formatFloat(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// formatTimestamp formats t in seconds, as required for the OpenMetrics exemplars.
+func formatTimestamp(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/float64(time.Second), 'f', -1, 64)
+}
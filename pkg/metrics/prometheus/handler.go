@@ -0,0 +1,81 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+const (
+	contentTypeText        = "text/plain; version=0.0.4; charset=utf-8"
+	contentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	mediaTypeOpenMetrics   = "application/openmetrics-text"
+)
+
+var (
+	ErrUnsupportedAggregation = errors.New("aggregation not supported by the prometheus format")
+	ErrConflictingMetric      = errors.New("conflicting metric types")
+)
+
+// Collector collects the metrics of a meter provider. It is implemented by sdkMetric.ManualReader,
+// which must be registered with the provider using otelmeter.WithReader, or sdkMetric.WithReader:
+//
+//	reader := sdkMetric.NewManualReader()
+//	mp, err := otelmeter.NewMeterProvider(cfg, nil, serviceName, otelmeter.WithReader(reader))
+//	...
+//	http.Handle("/metrics", prometheus.NewHandler(reader))
+type Collector interface {
+	Collect(ctx context.Context, rm *metricdata.ResourceMetrics) error
+}
+
+// NewHandler returns a handler collecting the metrics on every request. The OpenMetrics format is
+// served when the scraper accepts it, with the exemplars of the counters and histogram buckets,
+// otherwise the Prometheus text format is served.
+//
+// The metric names are sanitized and suffixed with their unit, e.g. "http.server.duration" in
+// "ms" becomes "http_server_duration_milliseconds", and the counters get the "_total" suffix.
+// The up-down counters are exposed as gauges, the exponential histograms are not supported.
+// The resource attributes are exposed as the labels of the "target_info" metric, and the name and
+// version of the meter of every series as its "otel_scope_name" and "otel_scope_version" labels,
+// left out when empty.
+func NewHandler(collector Collector) http.Handler {
+	return &handler{collector: collector}
+}
+
+type handler struct {
+	collector Collector
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var rm metricdata.ResourceMetrics
+	if err := h.collector.Collect(r.Context(), &rm); err != nil {
+		http.Error(w, fmt.Sprintf("failed to collect metrics: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	f, contentType := negotiate(r.Header.Get("Accept"))
+	var buf bytes.Buffer
+	if err := encode(&buf, &rm, f); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode metrics: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = buf.WriteTo(w)
+}
+
+// negotiate picks the OpenMetrics format when it is listed in the Accept header.
+func negotiate(accept string) (format, string) {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == mediaTypeOpenMetrics {
+			return formatOpenMetrics, contentTypeOpenMetrics
+		}
+	}
+	return formatText, contentTypeText
+}
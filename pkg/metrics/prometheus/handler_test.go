@@ -0,0 +1,210 @@
+package prometheus_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	metricsConfig "github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/metrics/otelmeter"
+	"github.com/nash-567/goObserve/pkg/metrics/prometheus"
+	tracingConfig "github.com/nash-567/goObserve/pkg/tracing/config"
+	tracingModel "github.com/nash-567/goObserve/pkg/tracing/model"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+func scrape(t *testing.T, handler http.Handler, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNewHandler_Text(t *testing.T) {
+	t.Parallel()
+	reader := sdkMetric.NewManualReader()
+	mp := sdkMetric.NewMeterProvider(
+		sdkMetric.WithReader(reader),
+		sdkMetric.WithResource(resource.NewSchemaless(attribute.String("service.name", "checkout"))),
+	)
+	meter := otelmeter.NewMeter(&metricsConfig.MetricsConfig{
+		InstrumentationLibrary: metricsConfig.InstrumentationLibraryConfig{Name: "checkout", Version: "1.2.0"},
+	}, mp)
+	ctx := context.Background()
+
+	requests, err := meter.Counter("http.requests",
+		model.WithUnit("{request}"), model.WithDescription("Number of \"HTTP\" requests."))
+	require.NoError(t, err)
+	requests.Add(ctx, 2, tracingModel.NewKeyValue("http.route", "/orders"), tracingModel.NewKeyValue("1st", "x"))
+	requests.Add(ctx, 1, tracingModel.NewKeyValue("http.route", "/users"))
+
+	duration, err := meter.Histogram("http.server.duration",
+		model.WithUnit("ms"), model.WithBucketBoundaries(10, 100))
+	require.NoError(t, err)
+	duration.Record(ctx, 5)
+	duration.Record(ctx, 50)
+	duration.Record(ctx, 500)
+
+	queue, err := meter.UpDownCounter("queue.size", model.WithUnit("By"))
+	require.NoError(t, err)
+	queue.Add(ctx, -1.5, tracingModel.NewKeyValue("queue", "a\"b\nc"))
+
+	rec := scrape(t, prometheus.NewHandler(reader), "text/plain")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP http_requests_total Number of "HTTP" requests.
+# TYPE http_requests_total counter
+http_requests_total{_1st="x",http_route="/orders",otel_scope_name="checkout",otel_scope_version="1.2.0"} 2
+http_requests_total{http_route="/users",otel_scope_name="checkout",otel_scope_version="1.2.0"} 1
+# TYPE http_server_duration_milliseconds histogram
+http_server_duration_milliseconds_bucket{otel_scope_name="checkout",otel_scope_version="1.2.0",le="10"} 1
+http_server_duration_milliseconds_bucket{otel_scope_name="checkout",otel_scope_version="1.2.0",le="100"} 2
+http_server_duration_milliseconds_bucket{otel_scope_name="checkout",otel_scope_version="1.2.0",le="+Inf"} 3
+http_server_duration_milliseconds_sum{otel_scope_name="checkout",otel_scope_version="1.2.0"} 555
+http_server_duration_milliseconds_count{otel_scope_name="checkout",otel_scope_version="1.2.0"} 3
+# TYPE queue_size_bytes gauge
+queue_size_bytes{otel_scope_name="checkout",otel_scope_version="1.2.0",queue="a\"b\nc"} -1.5
+# HELP target_info Target metadata
+# TYPE target_info gauge
+target_info{service_name="checkout"} 1
+`, rec.Body.String())
+}
+
+// staticCollector returns the same metrics on every collection.
+type staticCollector struct {
+	rm  metricdata.ResourceMetrics
+	err error
+}
+
+func (c staticCollector) Collect(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	*rm = c.rm
+	return c.err
+}
+
+func TestNewHandler_OpenMetrics(t *testing.T) {
+	t.Parallel()
+	traceID := []byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	spanID := []byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	at := time.Unix(1700000000, 500000000)
+	collector := staticCollector{rm: metricdata.ResourceMetrics{ScopeMetrics: []metricdata.ScopeMetrics{{
+		Metrics: []metricdata.Metrics{
+			{
+				Name: "jobs_total",
+				Unit: "1",
+				Data: metricdata.Sum[int64]{
+					IsMonotonic: true,
+					DataPoints: []metricdata.DataPoint[int64]{{
+						Value:     7,
+						Exemplars: []metricdata.Exemplar[int64]{{Value: 1, Time: at, TraceID: traceID, SpanID: spanID}},
+					}},
+				},
+			},
+			{
+				Name:        "rpc.duration",
+				Unit:        "s",
+				Description: "RPC latency.",
+				Data: metricdata.Histogram[float64]{DataPoints: []metricdata.HistogramDataPoint[float64]{{
+					Bounds:       []float64{0.1, 1},
+					BucketCounts: []uint64{0, 1, 1},
+					Count:        2,
+					Sum:          2.5,
+					Exemplars: []metricdata.Exemplar[float64]{{
+						Value:              0.5,
+						Time:               at,
+						TraceID:            traceID,
+						SpanID:             spanID,
+						FilteredAttributes: []attribute.KeyValue{attribute.String("user.id", "42")},
+					}},
+				}}},
+			},
+		},
+	}}}}
+
+	rec := scrape(t, prometheus.NewHandler(collector), "application/openmetrics-text;version=1.0.0,text/plain;q=0.5")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/openmetrics-text; version=1.0.0; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `# TYPE jobs counter
+jobs_total 7 # {trace_id="4bf92f3577b34da6a3ce929d0e0e4736",span_id="00f067aa0ba902b7"} 1 1700000000.5
+# HELP rpc_duration_seconds RPC latency.
+# TYPE rpc_duration_seconds histogram
+# UNIT rpc_duration_seconds seconds
+rpc_duration_seconds_bucket{le="0.1"} 0
+rpc_duration_seconds_bucket{le="1"} 1 # {trace_id="4bf92f3577b34da6a3ce929d0e0e4736",span_id="00f067aa0ba902b7",user_id="42"} 0.5 1700000000.5
+rpc_duration_seconds_bucket{le="+Inf"} 2
+rpc_duration_seconds_sum 2.5
+rpc_duration_seconds_count 2
+# EOF
+`, rec.Body.String())
+
+	// exemplars are not part of the Prometheus text format
+	rec = scrape(t, prometheus.NewHandler(collector), "")
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.NotContains(t, rec.Body.String(), "trace_id")
+	assert.Contains(t, rec.Body.String(), "# TYPE jobs_total counter\njobs_total 7\n")
+}
+
+func TestNewHandler_CollectError(t *testing.T) {
+	t.Parallel()
+	rec := scrape(t, prometheus.NewHandler(staticCollector{err: errors.New("reader is shutdown")}), "")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "reader is shutdown")
+}
+
+func TestNewHandler_TraceExemplars(t *testing.T) {
+	t.Parallel()
+	reader := sdkMetric.NewManualReader()
+	meter := otelmeter.NewMeter(&metricsConfig.MetricsConfig{}, sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)))
+	duration, err := meter.Histogram("rpc.duration", model.WithUnit("s"), model.WithBucketBoundaries(1))
+	require.NoError(t, err)
+
+	tracer := oteltracer.NewTracer(&tracingConfig.TracingConfig{}, sdkTrace.NewTracerProvider())
+	ctx, span := tracer.StartSpan(context.Background(), "rpc")
+	duration.Record(ctx, 0.5)
+	span.End()
+	spanContext := trace.SpanContextFromContext(ctx)
+
+	rec := scrape(t, prometheus.NewHandler(reader), "application/openmetrics-text")
+	assert.Contains(t, rec.Body.String(), `rpc_duration_seconds_bucket{le="1"} 1 # {trace_id="`+
+		spanContext.TraceID().String()+`",span_id="`+spanContext.SpanID().String()+`"} 0.5 `)
+}
+
+func TestNewHandler_Scopes(t *testing.T) {
+	t.Parallel()
+	reader := sdkMetric.NewManualReader()
+	mp := sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader), sdkMetric.WithResource(resource.Empty()))
+	ctx := context.Background()
+
+	// the same metric recorded by two libraries is told apart by the scope labels
+	for _, name := range []string{"orders", "payments"} {
+		meter := otelmeter.NewMeter(&metricsConfig.MetricsConfig{
+			InstrumentationLibrary: metricsConfig.InstrumentationLibraryConfig{Name: name},
+		}, mp)
+		jobs, err := meter.Counter("jobs")
+		require.NoError(t, err)
+		jobs.Add(ctx, 1)
+	}
+
+	rec := scrape(t, prometheus.NewHandler(reader), "text/plain")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `# TYPE jobs_total counter
+jobs_total{otel_scope_name="orders"} 1
+jobs_total{otel_scope_name="payments"} 1
+`, rec.Body.String())
+}
@@ -0,0 +1,137 @@
+package prometheus
+
+import (
+	"strings"
+	"unicode"
+)
+
+// unitSuffixes maps the UCUM units of the instruments to the Prometheus base unit names.
+// See https://opentelemetry.io/docs/specs/otel/compatibility/prometheus_and_openmetrics/.
+//
+//nolint:gochecknoglobals // lookup table
+var unitSuffixes = map[string]string{
+	"d":    "days",
+	"h":    "hours",
+	"min":  "minutes",
+	"s":    "seconds",
+	"ms":   "milliseconds",
+	"us":   "microseconds",
+	"ns":   "nanoseconds",
+	"By":   "bytes",
+	"KiBy": "kibibytes",
+	"MiBy": "mebibytes",
+	"GiBy": "gibibytes",
+	"TiBy": "tibibytes",
+	"KBy":  "kilobytes",
+	"MBy":  "megabytes",
+	"GBy":  "gigabytes",
+	"TBy":  "terabytes",
+	"m":    "meters",
+	"V":    "volts",
+	"A":    "amperes",
+	"J":    "joules",
+	"W":    "watts",
+	"g":    "grams",
+	"Cel":  "celsius",
+	"Hz":   "hertz",
+	"%":    "percent",
+}
+
+// perUnitSuffixes maps the denominators of the "x/y" units.
+//
+//nolint:gochecknoglobals // lookup table
+var perUnitSuffixes = map[string]string{
+	"s":  "second",
+	"m":  "minute",
+	"h":  "hour",
+	"d":  "day",
+	"w":  "week",
+	"mo": "month",
+	"y":  "year",
+}
+
+// unitSuffix converts a UCUM unit to the suffix of the metric name, e.g. "ms" to "milliseconds" and
+// "By/s" to "bytes_per_second". Annotations like "{request}" and the dimensionless "1" have no suffix.
+func unitSuffix(unit string) string {
+	unit = stripAnnotations(unit)
+	if unit == "" || unit == "1" {
+		return ""
+	}
+	num, den, isRate := strings.Cut(unit, "/")
+	suffix := unitSuffixes[num]
+	if suffix == "" {
+		suffix = num
+	}
+	if isRate {
+		per := perUnitSuffixes[den]
+		if per == "" {
+			per = den
+		}
+		if suffix == "" || suffix == "1" {
+			return sanitizeName("per_" + per)
+		}
+		suffix += "_per_" + per
+	}
+	return sanitizeName(suffix)
+}
+
+func stripAnnotations(unit string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range unit {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// metricName returns the Prometheus name of an instrument: sanitized, with the unit suffix
+// unless the name already ends with it. The counter "_total" suffix is added by the encoder.
+func metricName(name, unit string) string {
+	name = sanitizeName(name)
+	if suffix := unitSuffix(unit); suffix != "" && !strings.HasSuffix(name, "_"+suffix) {
+		name += "_" + suffix
+	}
+	return name
+}
+
+// sanitizeName replaces the characters not allowed in metric names with underscores,
+// collapses the consecutive underscores and prefixes the names starting with a digit.
+func sanitizeName(name string) string {
+	return sanitize(name, true)
+}
+
+// sanitizeLabel is sanitizeName for label names, colons are not allowed in them.
+func sanitizeLabel(name string) string {
+	return sanitize(name, false)
+}
+
+func sanitize(name string, allowColon bool) string {
+	var b strings.Builder
+	b.Grow(len(name))
+	for i, r := range name {
+		valid := r < unicode.MaxASCII &&
+			(unicode.IsLetter(r) || r == '_' || (allowColon && r == ':') || (i > 0 && unicode.IsDigit(r)))
+		if i == 0 && unicode.IsDigit(r) {
+			b.WriteByte('_')
+			valid = true
+		}
+		if !valid {
+			r = '_'
+		}
+		if r == '_' && strings.HasSuffix(b.String(), "_") {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
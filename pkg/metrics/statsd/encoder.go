@@ -0,0 +1,123 @@
+package statsd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+const (
+	typeCounter = "c"
+	typeGauge   = "g"
+)
+
+//nolint:gochecknoglobals // stateless replacers
+var (
+	// nameReplacer replaces the characters separating the parts of a line.
+	nameReplacer = strings.NewReplacer(":", "_", "|", "_", "@", "_", "#", "_", ",", "_", "\n", "_", " ", "_")
+	// tagReplacer keeps the colons of the tag values, only the first one separates the key.
+	tagReplacer = strings.NewReplacer("|", "_", "#", "_", ",", "_", "\n", "_")
+)
+
+// encode renders the metrics as StatsD lines, e.g. "prefix.http.requests:3|c|#method:GET".
+func encode(rm *metricdata.ResourceMetrics, prefix string) []string {
+	var lines []string
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			name := nameReplacer.Replace(prefix + m.Name)
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				lines = appendSum(lines, name, data)
+			case metricdata.Sum[float64]:
+				lines = appendSum(lines, name, data)
+			case metricdata.Gauge[int64]:
+				lines = appendPoints(lines, name, typeGauge, data.DataPoints)
+			case metricdata.Gauge[float64]:
+				lines = appendPoints(lines, name, typeGauge, data.DataPoints)
+			case metricdata.Histogram[int64]:
+				lines = appendHistogram(lines, name, data)
+			case metricdata.Histogram[float64]:
+				lines = appendHistogram(lines, name, data)
+			default:
+				otel.Handle(fmt.Errorf("%w: %s has aggregation %T", ErrUnsupportedAggregation, m.Name, m.Data))
+			}
+		}
+	}
+	return lines
+}
+
+func appendSum[N int64 | float64](lines []string, name string, sum metricdata.Sum[N]) []string {
+	if !sum.IsMonotonic {
+		return appendPoints(lines, name, typeGauge, sum.DataPoints)
+	}
+	// a delta counter has a data point per attribute set recorded since the previous export
+	return appendPoints(lines, name, typeCounter, sum.DataPoints)
+}
+
+func appendPoints[N int64 | float64](lines []string, name, typ string, points []metricdata.DataPoint[N]) []string {
+	for _, dp := range points {
+		lines = append(lines, line(name, formatValue(float64(dp.Value)), typ, tags(dp.Attributes)))
+	}
+	return lines
+}
+
+// appendHistogram sends the exact count and sum of the values recorded since the previous export as
+// counters, and their min and max as gauges, suffixed with ".count", ".sum", ".min" and ".max". The
+// bucket counts are not sent: StatsD lines carry values, not buckets, and any value standing for the
+// ones of a bucket would be made up. The percentiles need the values to be sent one by one, e.g. as
+// DogStatsD distributions by a StatsD client. The count and sum of the cumulative histograms, e.g. the
+// ones of the Go runtime, are totals since the process started and are sent as gauges instead.
+func appendHistogram[N int64 | float64](lines []string, name string, hist metricdata.Histogram[N]) []string {
+	typ := typeCounter
+	if hist.Temporality == metricdata.CumulativeTemporality {
+		typ = typeGauge
+	}
+	for _, dp := range hist.DataPoints {
+		if dp.Count == 0 {
+			continue
+		}
+		t := tags(dp.Attributes)
+		lines = append(lines,
+			line(name+".count", strconv.FormatUint(dp.Count, 10), typ, t),
+			line(name+".sum", formatValue(float64(dp.Sum)), typ, t),
+		)
+		if minValue, ok := dp.Min.Value(); ok {
+			lines = append(lines, line(name+".min", formatValue(float64(minValue)), typeGauge, t))
+		}
+		if maxValue, ok := dp.Max.Value(); ok {
+			lines = append(lines, line(name+".max", formatValue(float64(maxValue)), typeGauge, t))
+		}
+	}
+	return lines
+}
+
+func line(name, value, typ, tags string) string {
+	var b strings.Builder
+	b.WriteString(name + ":" + value + "|" + typ)
+	if tags != "" {
+		b.WriteString("|#" + tags)
+	}
+	return b.String()
+}
+
+// tags renders the attributes as DogStatsD tags, in the order of the set, i.e. sorted by key.
+func tags(set attribute.Set) string {
+	if set.Len() == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, set.Len())
+	iter := set.Iter()
+	for iter.Next() {
+		kv := iter.Attribute()
+		pairs = append(pairs, nameReplacer.Replace(string(kv.Key))+":"+tagReplacer.Replace(kv.Value.Emit()))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,168 @@
+package statsd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+const (
+	// DefaultAddress is the address of the local StatsD agent.
+	DefaultAddress = "localhost:8125"
+	// DefaultMaxPacketSize fits the packets in the 1500 bytes Ethernet MTU, IP and UDP headers included.
+	DefaultMaxPacketSize = 1432
+)
+
+var (
+	ErrUnsupportedAggregation = errors.New("aggregation not supported by statsd")
+	ErrExporterShutdown       = errors.New("exporter is shut down")
+)
+
+// Option configures the Exporter created by New.
+type Option func(*options)
+
+type options struct {
+	prefix        string
+	maxPacketSize int
+}
+
+// WithPrefix prepends prefix to the metric names, e.g. "checkout.".
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithMaxPacketSize sets the maximum size of the UDP packets, DefaultMaxPacketSize by default.
+// The lines are batched in packets up to this size, a longer line is sent in its own packet.
+func WithMaxPacketSize(size int) Option {
+	return func(o *options) {
+		o.maxPacketSize = size
+	}
+}
+
+// Exporter sends the metrics to a StatsD agent over UDP, with the attributes as DogStatsD tags:
+//
+//   - the counters are sent as StatsD counters ("c") of the increments since the previous export,
+//   - the up-down counters and the gauges as StatsD gauges ("g") of their current value,
+//   - the histograms as the counters of the count and sum of the values recorded since the previous
+//     export, and the gauges of their min and max, e.g. "duration.count", "duration.sum", "duration.min"
+//     and "duration.max". The bucket counts are lost.
+//
+// The exponential histograms are not supported.
+type Exporter struct {
+	conn net.Conn
+	options
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// New creates an exporter sending the metrics to address, DefaultAddress when empty.
+func New(address string, opts ...Option) (*Exporter, error) {
+	o := options{maxPacketSize: DefaultMaxPacketSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if address == "" {
+		address = DefaultAddress
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial statsd agent: %w", err)
+	}
+	return &Exporter{conn: conn, options: o}, nil
+}
+
+// Temporality returns the temporality of the instruments, see TemporalitySelector.
+func (e *Exporter) Temporality(kind metric.InstrumentKind) metricdata.Temporality {
+	return TemporalitySelector(kind)
+}
+
+// TemporalitySelector returns the delta temporality for the counters and histograms, as StatsD
+// aggregates their values itself, and the cumulative one for the instruments sent as gauges.
+func TemporalitySelector(kind metric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case metric.InstrumentKindUpDownCounter, metric.InstrumentKindObservableUpDownCounter,
+		metric.InstrumentKindGauge, metric.InstrumentKindObservableGauge:
+		return metricdata.CumulativeTemporality
+	default:
+		return metricdata.DeltaTemporality
+	}
+}
+
+// Aggregation returns the default aggregation of the instruments.
+//
+//nolint:ireturn // implements metric.Exporter
+func (e *Exporter) Aggregation(kind metric.InstrumentKind) metric.Aggregation {
+	return metric.DefaultAggregationSelector(kind)
+}
+
+// Export sends the metrics in packets of at most the maximum packet size.
+func (e *Exporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return ErrExporterShutdown
+	}
+
+	var errs []error
+	for _, packet := range batch(encode(rm, e.prefix), e.maxPacketSize) {
+		if _, err := e.conn.Write(packet); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to send metrics to statsd agent: %w", err)
+	}
+	return nil
+}
+
+// ForceFlush does nothing, the metrics are sent when exported.
+func (e *Exporter) ForceFlush(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Shutdown closes the connection, the later exports fail.
+func (e *Exporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if err := e.conn.Close(); err != nil {
+		return fmt.Errorf("failed to close statsd connection: %w", err)
+	}
+	return nil
+}
+
+// batch joins the lines with newlines in packets of at most maxSize bytes.
+func batch(lines []string, maxSize int) [][]byte {
+	var (
+		packets [][]byte
+		packet  []byte
+	)
+	for _, line := range lines {
+		if len(packet) > 0 && len(packet)+1+len(line) > maxSize {
+			packets = append(packets, packet)
+			packet = nil
+		}
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	if len(packet) > 0 {
+		packets = append(packets, packet)
+	}
+	return packets
+}
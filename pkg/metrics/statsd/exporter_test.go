@@ -0,0 +1,165 @@
+package statsd_test
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	metricsConfig "github.com/nash-567/goObserve/pkg/metrics/config"
+	"github.com/nash-567/goObserve/pkg/metrics/model"
+	"github.com/nash-567/goObserve/pkg/metrics/otelmeter"
+	"github.com/nash-567/goObserve/pkg/metrics/statsd"
+	tracingModel "github.com/nash-567/goObserve/pkg/tracing/model"
+)
+
+// listen starts a local UDP listener standing for the StatsD agent.
+func listen(t *testing.T) *net.UDPConn {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// receive reads the packets until none is received for a while.
+func receive(t *testing.T, conn *net.UDPConn) []string {
+	t.Helper()
+	var packets []string
+	buf := make([]byte, 65535)
+	for {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+		n, err := conn.Read(buf)
+		if err != nil {
+			return packets
+		}
+		packets = append(packets, string(buf[:n]))
+	}
+}
+
+func newMeter(t *testing.T, exporter sdkMetric.Exporter) (*otelmeter.Meter, *sdkMetric.MeterProvider) {
+	t.Helper()
+	mp := sdkMetric.NewMeterProvider(sdkMetric.WithReader(sdkMetric.NewPeriodicReader(exporter, sdkMetric.WithInterval(time.Hour))))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+	return otelmeter.NewMeter(&metricsConfig.MetricsConfig{}, mp), mp
+}
+
+func TestExporter(t *testing.T) {
+	t.Parallel()
+	agent := listen(t)
+	exporter, err := statsd.New(agent.LocalAddr().String(), statsd.WithPrefix("checkout."))
+	require.NoError(t, err)
+	meter, mp := newMeter(t, exporter)
+	ctx := context.Background()
+
+	requests, err := meter.Counter("http.requests")
+	require.NoError(t, err)
+	requests.Add(ctx, 2, tracingModel.NewKeyValue("method", "GET"), tracingModel.NewKeyValue("route", "/a|b"))
+	queue, err := meter.UpDownCounter("queue.size")
+	require.NoError(t, err)
+	queue.Add(ctx, 5)
+	queue.Add(ctx, -2)
+	temperature, err := meter.Gauge("temperature")
+	require.NoError(t, err)
+	temperature.Record(ctx, 21.5)
+	duration, err := meter.Histogram("duration", model.WithBucketBoundaries(10, 100))
+	require.NoError(t, err)
+	for _, v := range []float64{20, 40, 60, 500} {
+		duration.Record(ctx, v)
+	}
+	require.NoError(t, mp.ForceFlush(ctx))
+
+	// the histograms are sent as their exact count, sum, min and max, without the buckets
+	packets := receive(t, agent)
+	require.Len(t, packets, 1)
+	lines := strings.Split(packets[0], "\n")
+	sort.Strings(lines)
+	assert.Equal(t, []string{
+		"checkout.duration.count:4|c",
+		"checkout.duration.max:500|g",
+		"checkout.duration.min:20|g",
+		"checkout.duration.sum:620|c",
+		"checkout.http.requests:2|c|#method:GET,route:/a_b",
+		"checkout.queue.size:3|g",
+		"checkout.temperature:21.5|g",
+	}, lines)
+
+	// the counters and histograms are sent as increments, the gauges with their current value
+	requests.Add(ctx, 1, tracingModel.NewKeyValue("method", "GET"), tracingModel.NewKeyValue("route", "/a|b"))
+	require.NoError(t, mp.ForceFlush(ctx))
+	packets = receive(t, agent)
+	require.Len(t, packets, 1)
+	lines = strings.Split(packets[0], "\n")
+	sort.Strings(lines)
+	assert.Equal(t, []string{
+		"checkout.http.requests:1|c|#method:GET,route:/a_b",
+		"checkout.queue.size:3|g",
+		"checkout.temperature:21.5|g",
+	}, lines)
+}
+
+func TestExporter_Batching(t *testing.T) {
+	t.Parallel()
+	agent := listen(t)
+	exporter, err := statsd.New(agent.LocalAddr().String(), statsd.WithMaxPacketSize(64))
+	require.NoError(t, err)
+	meter, mp := newMeter(t, exporter)
+	ctx := context.Background()
+
+	for _, name := range []string{"first.counter", "second.counter", "third.counter", "fourth.counter"} {
+		counter, err := meter.Counter(name)
+		require.NoError(t, err)
+		counter.Add(ctx, 1, tracingModel.NewKeyValue("host", "web-1"))
+	}
+	require.NoError(t, mp.ForceFlush(ctx))
+
+	packets := receive(t, agent)
+	require.Len(t, packets, 2)
+	var lines []string
+	for _, packet := range packets {
+		assert.LessOrEqual(t, len(packet), 64)
+		lines = append(lines, strings.Split(packet, "\n")...)
+	}
+	assert.Len(t, lines, 4)
+}
+
+func TestExporter_Shutdown(t *testing.T) {
+	t.Parallel()
+	exporter, err := statsd.New(listen(t).LocalAddr().String())
+	require.NoError(t, err)
+	require.NoError(t, exporter.Shutdown(context.Background()))
+	require.NoError(t, exporter.Shutdown(context.Background()))
+	_, mp := newMeter(t, exporter)
+	require.ErrorIs(t, mp.ForceFlush(context.Background()), statsd.ErrExporterShutdown)
+}
+
+func TestExporter_CumulativeHistogram(t *testing.T) {
+	t.Parallel()
+	agent := listen(t)
+	exporter, err := statsd.New(agent.LocalAddr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = exporter.Shutdown(context.Background()) })
+
+	// e.g. the runtime histograms, whose totals are sent as gauges
+	require.NoError(t, exporter.Export(context.Background(), &metricdata.ResourceMetrics{
+		ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{{
+			Name: "go.gc.pause.duration",
+			Data: metricdata.Histogram[float64]{
+				Temporality: metricdata.CumulativeTemporality,
+				DataPoints: []metricdata.HistogramDataPoint[float64]{{
+					Bounds: []float64{0.001}, BucketCounts: []uint64{2, 1}, Count: 3, Sum: 0.001,
+				}},
+			},
+		}}}},
+	}))
+	packets := receive(t, agent)
+	require.Len(t, packets, 1)
+	assert.Equal(t, "go.gc.pause.duration.count:3|g\ngo.gc.pause.duration.sum:0.001|g", packets[0])
+}
@@ -9,6 +9,58 @@ type TracingConfig struct {
 	Enabled                bool                         `koanf:"Enabled"`
 	InstrumentationLibrary InstrumentationLibraryConfig `koanf:"InstrumentationLibrary"`
 	ExporterConfig         TraceExporterConfig          `koanf:"ExporterConfig"`
+	// AdditionalExporters receive the same spans as ExporterConfig, each through its own span processor.
+	AdditionalExporters []TraceExporterConfig `koanf:"AdditionalExporters"`
+	// TailSampling buffers the spans of every trace and exports only the interesting traces.
+	TailSampling TailSamplingConfig `koanf:"TailSampling"`
+	// SpanLimits bounds the size of every span, the SDK defaults are used for the zero fields.
+	SpanLimits SpanLimitsConfig `koanf:"SpanLimits"`
+}
+
+// SpanLimitsConfig is the configuration for the limits of a span. A zero field keeps the SDK
+// default and a negative one removes the limit. The attributes and events over the limits are dropped.
+type SpanLimitsConfig struct {
+	// AttributeValueLengthLimit is the number of characters string attribute values are truncated to, unlimited by default.
+	AttributeValueLengthLimit int `koanf:"AttributeValueLengthLimit"`
+	// AttributeCountLimit is the number of attributes of a span, 128 by default.
+	AttributeCountLimit int `koanf:"AttributeCountLimit"`
+	// EventCountLimit is the number of events of a span, 128 by default. The oldest events are dropped.
+	EventCountLimit int `koanf:"EventCountLimit"`
+	// LinkCountLimit is the number of links of a span, 128 by default. The oldest links are dropped.
+	LinkCountLimit int `koanf:"LinkCountLimit"`
+	// AttributePerEventCountLimit is the number of attributes of an event, 128 by default.
+	AttributePerEventCountLimit int `koanf:"AttributePerEventCountLimit"`
+	// AttributePerLinkCountLimit is the number of attributes of a link, 128 by default.
+	AttributePerLinkCountLimit int `koanf:"AttributePerLinkCountLimit"`
+}
+
+// TailSamplingConfig is the configuration for the tail sampler. The spans of a trace are buffered
+// until DecisionWait has elapsed since its first span ended, then the whole trace is exported if
+// it matches any of the policies: Errors, MinDuration, Attributes or the Ratio baseline.
+type TailSamplingConfig struct {
+	Enabled bool `koanf:"Enabled"`
+	// DecisionWait is the time spans are buffered before deciding, 10s by default.
+	DecisionWait time.Duration `koanf:"DecisionWait"`
+	// MaxTraces is the number of traces buffered, 10000 by default. The decision for the oldest
+	// trace is taken early when a new trace does not fit.
+	MaxTraces int `koanf:"MaxTraces"`
+	// MaxSpansPerTrace is the number of spans buffered per trace, 1000 by default.
+	// The spans ended once a trace is full are dropped.
+	MaxSpansPerTrace int `koanf:"MaxSpansPerTrace"`
+	// Errors keeps the traces containing a span ended with an error status.
+	Errors bool `koanf:"Errors"`
+	// MinDuration keeps the traces whose root span lasted longer than MinDuration, zero disables the policy.
+	MinDuration time.Duration `koanf:"MinDuration"`
+	// Attributes keeps the traces containing a span with one of the attribute values.
+	Attributes []TailSamplingAttributeConfig `koanf:"Attributes"`
+	// Ratio is the fraction of the remaining traces kept anyway, between 0 and 1.
+	Ratio float64 `koanf:"Ratio"`
+}
+
+// TailSamplingAttributeConfig matches the spans with an attribute Key set to one of Values.
+type TailSamplingAttributeConfig struct {
+	Key    string   `koanf:"Key"`
+	Values []string `koanf:"Values"`
 }
 
 type InstrumentationLibraryConfig struct {
@@ -21,10 +73,76 @@ type InstrumentationLibraryConfig struct {
 type TraceExporterConfig struct {
 	Type model.TraceExporterType `koanf:"Type"`
 	// in case of stdout exporter, rest of the config is ignored
-	EndpointURL  string                   `koanf:"EndpointURL"`
-	Timeout      time.Duration            `koanf:"Timeout"`
-	RetryConfig  TraceExporterRetryConfig `koanf:"RetryConfig"`
-	BatchTimeout time.Duration            `koanf:"BatchTimeout"`
+	EndpointURL string                   `koanf:"EndpointURL"`
+	Timeout     time.Duration            `koanf:"Timeout"`
+	RetryConfig TraceExporterRetryConfig `koanf:"RetryConfig"`
+	// Processor selects how ended spans reach the exporter, batch is the default.
+	// The remaining batch settings are ignored by the simple processor.
+	Processor    model.SpanProcessorType `koanf:"Processor"`
+	BatchTimeout time.Duration           `koanf:"BatchTimeout"`
+	// MaxQueueSize is the number of spans buffered before new spans are dropped, or
+	// the caller is blocked when BlockOnQueueFull is set.
+	MaxQueueSize       int           `koanf:"MaxQueueSize"`
+	MaxExportBatchSize int           `koanf:"MaxExportBatchSize"`
+	ExportTimeout      time.Duration `koanf:"ExportTimeout"`
+	BlockOnQueueFull   bool          `koanf:"BlockOnQueueFull"`
+	// Headers are sent with every export request, e.g. bearer tokens or API keys.
+	Headers     map[string]string      `koanf:"Headers"`
+	Compression model.CompressionType  `koanf:"Compression"`
+	TLS         TraceExporterTLSConfig `koanf:"TLS"`
+	// Insecure disables transport security, TLS is ignored when set. Meant for local development.
+	Insecure bool `koanf:"Insecure"`
+	// Filter restricts the spans sent to this exporter, all spans are exported by default.
+	Filter TraceExporterFilterConfig `koanf:"Filter"`
+	// File is only used by the file exporter.
+	File TraceExporterFileConfig `koanf:"File"`
+	// Queue persists the spans on disk until the endpoint accepts them, only used by the http exporter.
+	Queue TraceExporterQueueConfig `koanf:"Queue"`
+}
+
+// TraceExporterQueueConfig is the configuration for the on-disk queue in front of the trace exporter.
+type TraceExporterQueueConfig struct {
+	Enabled bool `koanf:"Enabled"`
+	// Dir is the directory the queued spans are written to. It must not be shared between processes.
+	Dir string `koanf:"Dir"`
+	// MaxSize is the size in bytes of the queue, the oldest spans are dropped once it is exceeded.
+	// Zero means unbounded.
+	MaxSize int64 `koanf:"MaxSize"`
+	// MaxAge is the time after which queued spans are dropped instead of sent. Zero means no limit.
+	MaxAge time.Duration `koanf:"MaxAge"`
+	// RetryInterval is the delay before sending again after a failure, 5s by default.
+	RetryInterval time.Duration `koanf:"RetryInterval"`
+}
+
+// TraceExporterFileConfig is the configuration for the file trace exporter.
+type TraceExporterFileConfig struct {
+	// Path of the file the spans are appended to, "traces.jsonl" by default.
+	Path string `koanf:"Path"`
+	// MaxSize is the size in bytes after which the file is rotated, zero disables rotation.
+	MaxSize int64 `koanf:"MaxSize"`
+	// MaxBackups is the number of rotated files kept, zero keeps all of them.
+	MaxBackups int `koanf:"MaxBackups"`
+	// Compress gzips the rotated files.
+	Compress bool `koanf:"Compress"`
+}
+
+// TraceExporterFilterConfig is the configuration for the spans sent to a trace exporter.
+// A span is exported only if it matches every configured condition.
+type TraceExporterFilterConfig struct {
+	// ErrorsOnly exports only the spans ended with an error status.
+	ErrorsOnly bool `koanf:"ErrorsOnly"`
+	// MinDuration exports only the spans lasting at least MinDuration.
+	MinDuration time.Duration `koanf:"MinDuration"`
+}
+
+// TraceExporterTLSConfig is the configuration for the trace exporter transport security.
+type TraceExporterTLSConfig struct {
+	// CAFile is the path to a PEM encoded CA bundle used to verify the collector certificate.
+	CAFile string `koanf:"CAFile"`
+	// CertFile and KeyFile are the paths to a PEM encoded client certificate and key for mTLS.
+	CertFile           string `koanf:"CertFile"`
+	KeyFile            string `koanf:"KeyFile"`
+	InsecureSkipVerify bool   `koanf:"InsecureSkipVerify"`
 }
 
 // TraceExporterRetryConfig is the configuration for the trace exporter retry.
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// SetBaggage returns a copy of ctx with the baggage member key=value added, replacing the
+// existing value of key. The baggage is propagated to the downstream services along with the
+// trace context, so it must not contain sensitive data.
+func SetBaggage(ctx context.Context, key, value string) (context.Context, error) {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx, fmt.Errorf("failed to create baggage member %s: %w", key, err)
+	}
+	b, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, fmt.Errorf("failed to set baggage member %s: %w", key, err)
+	}
+	return baggage.ContextWithBaggage(ctx, b), nil
+}
+
+// Baggage returns the baggage members of ctx as string key-values, sorted by key.
+func Baggage(ctx context.Context) []KeyValue {
+	members := baggage.FromContext(ctx).Members()
+	sort.Slice(members, func(i, j int) bool { return members[i].Key() < members[j].Key() })
+
+	kvs := make([]KeyValue, len(members))
+	for i, m := range members {
+		kvs[i] = NewKeyValue(m.Key(), m.Value())
+	}
+	return kvs
+}
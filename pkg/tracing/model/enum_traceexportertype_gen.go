@@ -8,11 +8,11 @@ import (
 	"strings"
 )
 
-const _TraceExporterTypeName = "stdouthttp"
+const _TraceExporterTypeName = "stdouthttpfilezipkin"
 
-var _TraceExporterTypeIndex = [...]uint8{0, 6, 10}
+var _TraceExporterTypeIndex = [...]uint8{0, 6, 10, 14, 20}
 
-const _TraceExporterTypeLowerName = "stdouthttp"
+const _TraceExporterTypeLowerName = "stdouthttpfilezipkin"
 
 func (i TraceExporterType) String() string {
 	if i < 0 || i >= TraceExporterType(len(_TraceExporterTypeIndex)-1) {
@@ -27,20 +27,28 @@ func _TraceExporterTypeNoOp() {
 	var x [1]struct{}
 	_ = x[TraceExporterTypeStdout-(0)]
 	_ = x[TraceExporterTypeHTTP-(1)]
+	_ = x[TraceExporterTypeFile-(2)]
+	_ = x[TraceExporterTypeZipkin-(3)]
 }
 
-var _TraceExporterTypeValues = []TraceExporterType{TraceExporterTypeStdout, TraceExporterTypeHTTP}
+var _TraceExporterTypeValues = []TraceExporterType{TraceExporterTypeStdout, TraceExporterTypeHTTP, TraceExporterTypeFile, TraceExporterTypeZipkin}
 
 var _TraceExporterTypeNameToValueMap = map[string]TraceExporterType{
-	_TraceExporterTypeName[0:6]:       TraceExporterTypeStdout,
-	_TraceExporterTypeLowerName[0:6]:  TraceExporterTypeStdout,
-	_TraceExporterTypeName[6:10]:      TraceExporterTypeHTTP,
-	_TraceExporterTypeLowerName[6:10]: TraceExporterTypeHTTP,
+	_TraceExporterTypeName[0:6]:        TraceExporterTypeStdout,
+	_TraceExporterTypeLowerName[0:6]:   TraceExporterTypeStdout,
+	_TraceExporterTypeName[6:10]:       TraceExporterTypeHTTP,
+	_TraceExporterTypeLowerName[6:10]:  TraceExporterTypeHTTP,
+	_TraceExporterTypeName[10:14]:      TraceExporterTypeFile,
+	_TraceExporterTypeLowerName[10:14]: TraceExporterTypeFile,
+	_TraceExporterTypeName[14:20]:      TraceExporterTypeZipkin,
+	_TraceExporterTypeLowerName[14:20]: TraceExporterTypeZipkin,
 }
 
 var _TraceExporterTypeNames = []string{
 	_TraceExporterTypeName[0:6],
 	_TraceExporterTypeName[6:10],
+	_TraceExporterTypeName[10:14],
+	_TraceExporterTypeName[14:20],
 }
 
 // TraceExporterTypeString retrieves an enum value from the enum constants string name.
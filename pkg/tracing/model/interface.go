@@ -16,6 +16,9 @@ type Tracer interface {
 	//
 	// Any Span that is created MUST also be ended. This is the responsibility of the user.
 	StartSpan(ctx context.Context, spanName string, opts ...SpanStartOption) (context.Context, Span)
+	// SpanFromContext returns the current Span of ctx, whichever instrumentation started it.
+	// It returns a non-recording Span when ctx has none.
+	SpanFromContext(ctx context.Context) Span
 }
 
 type Span interface {
@@ -28,11 +31,17 @@ type Span interface {
 	// true if the Span is active and events can be recorded.
 	IsRecording() bool
 	// RecordError will record err as an exception span event for this span.
-	RecordError(err error)
+	// WithStackTrace(true) adds the stack trace of the caller to the event.
+	RecordError(err error, opts ...EventOption)
+	// SetStatus sets the status of the Span in the form of a code and a
+	// description. The description is only kept for StatusCodeError.
+	SetStatus(code StatusCode, description string)
 	// SetAttributes sets kv as attributes of the Span. If a key from kv
 	// already exists for an attribute of the Span it will be overwritten with
 	// the value contained in kv.
 	SetAttributes(attributes ...KeyValue)
 	// AddEvent adds an event with the provided name and options.
 	AddEvent(name string, opts ...EventOption)
+	// SetName sets the name of the Span, e.g. once the route of a server request is known.
+	SetName(name string)
 }
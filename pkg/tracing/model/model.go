@@ -2,6 +2,11 @@ package model
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
 	"go.opentelemetry.io/otel/attribute"
 )
 
@@ -11,11 +16,40 @@ type TraceExporterType int8
 const (
 	TraceExporterTypeStdout TraceExporterType = iota
 	TraceExporterTypeHTTP
+	TraceExporterTypeFile
+	TraceExporterTypeZipkin
 )
 
 //go:generate enumer -type=TraceExporterType -json -text -yaml -trimprefix=TraceExporterType -transform=snake -output=enum_traceexportertype_gen.go
+
+// CompressionType is an enum for the compression applied to exported payloads.
+type CompressionType int8
+
+const (
+	CompressionTypeNone CompressionType = iota
+	CompressionTypeGzip
+)
+
+//go:generate enumer -type=CompressionType -json -text -yaml -trimprefix=CompressionType -transform=snake -output=enum_compressiontype_gen.go
+
+// SpanProcessorType is an enum for the way ended spans are handed to the exporter.
+type SpanProcessorType int8
+
+const (
+	// SpanProcessorTypeBatch queues ended spans and exports them in batches in the background.
+	SpanProcessorTypeBatch SpanProcessorType = iota
+	// SpanProcessorTypeSimple exports every span synchronously when it ends.
+	// Only meant for debugging, it blocks the caller for the duration of the export.
+	SpanProcessorTypeSimple
+)
+
+//go:generate enumer -type=SpanProcessorType -json -text -yaml -trimprefix=SpanProcessorType -transform=snake -output=enum_spanprocessortype_gen.go
+
+// Value is the set of types accepted by NewKeyValue. Durations are stored as milliseconds,
+// times as RFC 3339 strings, and unsigned integers larger than math.MaxInt64 as strings.
 type Value interface {
-	int | int64 | float64 | bool | string | []int64 | []float64 | []bool | []string
+	int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | float32 | float64 | bool | string |
+		time.Duration | time.Time | []int | []int64 | []float64 | []bool | []string
 }
 
 type KeyValue struct {
@@ -28,20 +62,135 @@ func NewKeyValue[T Value](key string, value T) KeyValue {
 	}
 }
 
+// NewKeyValueFromAttribute wraps an OpenTelemetry attribute.
+func NewKeyValueFromAttribute(kv attribute.KeyValue) KeyValue {
+	return KeyValue{keyValue: kv}
+}
+
+// String creates a string KeyValue.
+func String(key, value string) KeyValue {
+	return KeyValue{keyValue: attribute.String(key, value)}
+}
+
+// Bool creates a bool KeyValue.
+func Bool(key string, value bool) KeyValue {
+	return KeyValue{keyValue: attribute.Bool(key, value)}
+}
+
+// Int creates an int KeyValue.
+func Int(key string, value int) KeyValue {
+	return KeyValue{keyValue: attribute.Int(key, value)}
+}
+
+// Int64 creates an int64 KeyValue.
+func Int64(key string, value int64) KeyValue {
+	return KeyValue{keyValue: attribute.Int64(key, value)}
+}
+
+// Uint64 creates an integer KeyValue, or a string one when value is larger than math.MaxInt64.
+func Uint64(key string, value uint64) KeyValue {
+	return KeyValue{keyValue: uint64Attribute(key, value)}
+}
+
+// Float64 creates a float64 KeyValue.
+func Float64(key string, value float64) KeyValue {
+	return KeyValue{keyValue: attribute.Float64(key, value)}
+}
+
+// Duration creates a KeyValue holding value in milliseconds, as a float64 to keep the sub-millisecond part.
+func Duration(key string, value time.Duration) KeyValue {
+	return KeyValue{keyValue: durationAttribute(key, value)}
+}
+
+// Time creates a KeyValue holding value as an RFC 3339 string, with nanoseconds.
+func Time(key string, value time.Time) KeyValue {
+	return KeyValue{keyValue: attribute.String(key, value.Format(time.RFC3339Nano))}
+}
+
+// Error creates a KeyValue holding the message of err, an empty string when err is nil.
+func Error(key string, err error) KeyValue {
+	if err == nil {
+		return String(key, "")
+	}
+	return String(key, err.Error())
+}
+
+// Stringer creates a KeyValue holding value.String().
+func Stringer(key string, value fmt.Stringer) KeyValue {
+	return KeyValue{keyValue: attribute.Stringer(key, value)}
+}
+
+// Any creates a KeyValue from a value of any type. The types accepted by NewKeyValue, error and
+// fmt.Stringer are converted like their typed constructors, the other types are formatted with fmt.
+func Any(key string, value any) KeyValue {
+	return KeyValue{keyValue: toAttributeKeyValue(key, value)}
+}
+
+// Map flattens m into one KeyValue per leaf, the keys of the nested maps are joined with dots,
+// e.g. Map("user", {"address": {"city": "Paris"}}) gives "user.address.city"="Paris".
+// The leaves are converted with Any, and the result is sorted by key.
+func Map(key string, m map[string]any) []KeyValue {
+	var kvs []KeyValue
+	flatten(key, m, &kvs)
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].keyValue.Key < kvs[j].keyValue.Key })
+	return kvs
+}
+
+func flatten(prefix string, m map[string]any, kvs *[]KeyValue) {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]any); ok {
+			flatten(key, nested, kvs)
+			continue
+		}
+		*kvs = append(*kvs, Any(key, v))
+	}
+}
+
 func (kv KeyValue) GetAttributeKeyValue() attribute.KeyValue {
 	return kv.keyValue
 }
 
-func toAttributeKeyValue[T Value](key string, value T) attribute.KeyValue {
-	switch v := any(value).(type) {
+//nolint:cyclop // one case per supported type
+func toAttributeKeyValue(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case int:
+		return attribute.Int(key, v)
+	case int8:
+		return attribute.Int64(key, int64(v))
+	case int16:
+		return attribute.Int64(key, int64(v))
+	case int32:
+		return attribute.Int64(key, int64(v))
 	case int64:
 		return attribute.Int64(key, v)
+	case uint:
+		return uint64Attribute(key, uint64(v))
+	case uint8:
+		return attribute.Int64(key, int64(v))
+	case uint16:
+		return attribute.Int64(key, int64(v))
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case uint64:
+		return uint64Attribute(key, v)
+	case float32:
+		return attribute.Float64(key, float64(v))
 	case float64:
 		return attribute.Float64(key, v)
 	case bool:
 		return attribute.Bool(key, v)
 	case string:
 		return attribute.String(key, v)
+	case time.Duration:
+		return durationAttribute(key, v)
+	case time.Time:
+		return attribute.String(key, v.Format(time.RFC3339Nano))
+	case []int:
+		return attribute.IntSlice(key, v)
 	case []int64:
 		return attribute.Int64Slice(key, v)
 	case []float64:
@@ -50,7 +199,22 @@ func toAttributeKeyValue[T Value](key string, value T) attribute.KeyValue {
 		return attribute.BoolSlice(key, v)
 	case []string:
 		return attribute.StringSlice(key, v)
+	case error:
+		return attribute.String(key, v.Error())
+	case fmt.Stringer:
+		return attribute.Stringer(key, v)
 	default:
 		return attribute.String(key, fmt.Sprintf("%v", v))
 	}
 }
+
+func uint64Attribute(key string, value uint64) attribute.KeyValue {
+	if value > math.MaxInt64 {
+		return attribute.String(key, strconv.FormatUint(value, 10))
+	}
+	return attribute.Int64(key, int64(value))
+}
+
+func durationAttribute(key string, value time.Duration) attribute.KeyValue {
+	return attribute.Float64(key, float64(value)/float64(time.Millisecond))
+}
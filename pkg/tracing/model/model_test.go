@@ -0,0 +1,86 @@
+package model_test
+
+import (
+	"errors"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+)
+
+func TestNewKeyValue(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2024, 5, 1, 12, 30, 0, 500, time.UTC)
+	tests := []struct {
+		name string
+		got  model.KeyValue
+		want attribute.KeyValue
+	}{
+		{"int", model.NewKeyValue("k", 42), attribute.Int("k", 42)},
+		{"int32", model.NewKeyValue("k", int32(-7)), attribute.Int64("k", -7)},
+		{"uint", model.NewKeyValue("k", uint(7)), attribute.Int64("k", 7)},
+		{"uint64 max", model.NewKeyValue("k", uint64(math.MaxUint64)), attribute.String("k", "18446744073709551615")},
+		{"float32", model.NewKeyValue("k", float32(1.5)), attribute.Float64("k", 1.5)},
+		{"duration", model.NewKeyValue("k", 1500*time.Microsecond), attribute.Float64("k", 1.5)},
+		{"time", model.NewKeyValue("k", at), attribute.String("k", "2024-05-01T12:30:00.0000005Z")},
+		{"int slice", model.NewKeyValue("k", []int{1, 2}), attribute.IntSlice("k", []int{1, 2})},
+		{"string slice", model.NewKeyValue("k", []string{"a"}), attribute.StringSlice("k", []string{"a"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.got.GetAttributeKeyValue())
+		})
+	}
+}
+
+func TestTypedConstructors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		got  model.KeyValue
+		want attribute.KeyValue
+	}{
+		{"string", model.String("k", "v"), attribute.String("k", "v")},
+		{"bool", model.Bool("k", true), attribute.Bool("k", true)},
+		{"int", model.Int("k", 3), attribute.Int("k", 3)},
+		{"int64", model.Int64("k", 3), attribute.Int64("k", 3)},
+		{"uint64", model.Uint64("k", 3), attribute.Int64("k", 3)},
+		{"float64", model.Float64("k", 0.5), attribute.Float64("k", 0.5)},
+		{"duration", model.Duration("k", 2*time.Second), attribute.Float64("k", 2000)},
+		{"time", model.Time("k", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)), attribute.String("k", "2024-05-01T00:00:00Z")},
+		{"error", model.Error("k", errors.New("boom")), attribute.String("k", "boom")},
+		{"nil error", model.Error("k", nil), attribute.String("k", "")},
+		{"stringer", model.Stringer("k", net.IPv4(10, 0, 0, 1)), attribute.String("k", "10.0.0.1")},
+		{"any error", model.Any("k", errors.New("boom")), attribute.String("k", "boom")},
+		{"any int", model.Any("k", 1), attribute.Int("k", 1)},
+		{"any struct", model.Any("k", struct{ A int }{1}), attribute.String("k", "{1}")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.got.GetAttributeKeyValue())
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+	got := model.Map("user", map[string]any{
+		"id": 42,
+		"address": map[string]any{
+			"city": "Paris",
+			"geo":  map[string]any{"lat": 48.85},
+		},
+	})
+	assert.Equal(t, []model.KeyValue{
+		model.String("user.address.city", "Paris"),
+		model.Float64("user.address.geo.lat", 48.85),
+		model.Int("user.id", 42),
+	}, got)
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// SpanProcessor hooks into the lifecycle of every span of a trace provider, before the spans
+// are handed to the exporters. It is used to enrich the spans, e.g. with global attributes,
+// and to filter them, e.g. to drop health checks. The methods are called synchronously,
+// so they must not block.
+type SpanProcessor interface {
+	// OnStart is called when a span is started. ctx is the context the span was started with,
+	// span can be modified, e.g. with SetAttributes.
+	OnStart(ctx context.Context, span Span)
+	// OnEnd is called when a span is ended. Returning false drops the span: it is neither
+	// passed to the next processors nor exported.
+	OnEnd(span ReadOnlySpan) bool
+}
+
+// ReadOnlySpan is the view of an ended span given to SpanProcessor.OnEnd.
+type ReadOnlySpan interface {
+	// Name returns the name of the span.
+	Name() string
+	// TraceID returns the hex encoded trace ID of the span.
+	TraceID() string
+	// SpanID returns the hex encoded ID of the span.
+	SpanID() string
+	// ParentSpanID returns the hex encoded ID of the parent span, empty for a root span.
+	ParentSpanID() string
+	// SpanKind returns the role of the span in the trace.
+	SpanKind() SpanKind
+	// StartTime returns the time the span started.
+	StartTime() time.Time
+	// EndTime returns the time the span ended.
+	EndTime() time.Time
+	// Attributes returns the attributes of the span.
+	Attributes() []KeyValue
+	// Status returns the status code and description of the span.
+	Status() (StatusCode, string)
+}
@@ -2,6 +2,36 @@ package model
 
 import "time"
 
+// StatusCode is the status of a Span. Its values match the OpenTelemetry status codes.
+type StatusCode uint32
+
+const (
+	// StatusCodeUnset is the default status of a Span.
+	StatusCodeUnset StatusCode = iota
+	// StatusCodeError indicates the operation represented by the Span failed.
+	StatusCodeError
+	// StatusCodeOk indicates the operation was explicitly validated as successful.
+	StatusCodeOk
+)
+
+// SpanKind is the role of a Span in a trace. Its values match the OpenTelemetry span kinds.
+type SpanKind int
+
+const (
+	// SpanKindUnspecified is treated as SpanKindInternal.
+	SpanKindUnspecified SpanKind = iota
+	// SpanKindInternal is an operation internal to an application.
+	SpanKindInternal
+	// SpanKindServer is the server side handling of a synchronous request, e.g. an incoming RPC.
+	SpanKindServer
+	// SpanKindClient is a synchronous request to a remote service, e.g. an outgoing RPC.
+	SpanKindClient
+	// SpanKindProducer is the creation of an asynchronous message, e.g. a queue publish.
+	SpanKindProducer
+	// SpanKindConsumer is the processing of an asynchronous message.
+	SpanKindConsumer
+)
+
 // SpanStartOption applies an option to a SpanConfig. These options are applicable
 // only when the span is created.
 type SpanStartOption interface {
@@ -20,6 +50,7 @@ type SpanConfig struct {
 	timestamp  time.Time
 	newRoot    bool
 	stackTrace bool
+	spanKind   SpanKind
 }
 
 // Attributes describe the associated qualities of a Span.
@@ -44,12 +75,25 @@ func (cfg *SpanConfig) StackTrace() bool {
 	return cfg.stackTrace
 }
 
+// SpanKind is the role a Span plays in a Trace.
+func (cfg *SpanConfig) SpanKind() SpanKind {
+	return cfg.spanKind
+}
+
 type spanStartOptionFunc func(SpanConfig) SpanConfig
 
 func (fn spanStartOptionFunc) applySpanStart(cfg SpanConfig) SpanConfig {
 	return fn(cfg)
 }
 
+// WithSpanKind sets the SpanKind of a Span.
+func WithSpanKind(kind SpanKind) SpanStartOption {
+	return spanStartOptionFunc(func(cfg SpanConfig) SpanConfig {
+		cfg.spanKind = kind
+		return cfg
+	})
+}
+
 // NewSpanStartConfig applies all the options to a returned SpanConfig.
 // No validation is performed on the returned SpanConfig (e.g. no uniqueness
 // checking or bounding of data), it is left to the SDK to perform this
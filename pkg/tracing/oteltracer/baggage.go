@@ -0,0 +1,34 @@
+package oteltracer
+
+import (
+	"context"
+
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// baggageSpanProcessor copies the selected baggage members of the parent context onto the
+// attributes of the started spans.
+type baggageSpanProcessor struct {
+	keys []string
+}
+
+func newBaggageSpanProcessor(keys []string) *baggageSpanProcessor {
+	return &baggageSpanProcessor{keys: keys}
+}
+
+func (p *baggageSpanProcessor) OnStart(parent context.Context, span model.Span) {
+	b := baggage.FromContext(parent)
+	if b.Len() == 0 {
+		return
+	}
+	for _, key := range p.keys {
+		if m := b.Member(key); m.Key() != "" {
+			span.SetAttributes(model.NewKeyValue(key, m.Value()))
+		}
+	}
+}
+
+func (p *baggageSpanProcessor) OnEnd(model.ReadOnlySpan) bool {
+	return true
+}
@@ -1,14 +1,19 @@
 package oteltracer
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"github.com/nash-567/goObserve/pkg/tracing/config"
 	"github.com/nash-567/goObserve/pkg/tracing/model"
 
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
 	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -17,17 +22,45 @@ var ErrUnknownTraceExporterType = fmt.Errorf("unknown trace exporter type")
 // NewTraceExporter creates a new trace exporter based on the provided configuration.
 // stdout exporter exports the spans to the stdout at regular intervals, the interval is configurable ExporterConfig.BatchTimeout.
 // http exporter exports the spans to the specified endpoint.
+// file exporter writes the spans to a local file as OTLP/JSON lines.
+// zipkin exporter posts the spans in Zipkin v2 JSON format to the specified endpoint.
 func NewTraceExporter(ctx context.Context, cfg *config.TracingConfig) (sdkTrace.SpanExporter, error) {
+	return newTraceExporter(ctx, &cfg.ExporterConfig)
+}
+
+// NewAdditionalTraceExporters creates an exporter for every entry of cfg.AdditionalExporters, in the same order.
+// The result is meant to be passed to NewTraceProvider using WithAdditionalExporters.
+func NewAdditionalTraceExporters(ctx context.Context, cfg *config.TracingConfig) ([]sdkTrace.SpanExporter, error) {
+	exporters := make([]sdkTrace.SpanExporter, 0, len(cfg.AdditionalExporters))
+	for i := range cfg.AdditionalExporters {
+		exporter, err := newTraceExporter(ctx, &cfg.AdditionalExporters[i])
+		if err != nil {
+			for _, created := range exporters {
+				_ = created.Shutdown(ctx)
+			}
+			return nil, fmt.Errorf("additional exporter %d: %w", i, err)
+		}
+		exporters = append(exporters, exporter)
+	}
+	return exporters, nil
+}
+
+//nolint:ireturn // the exporter type depends on the configuration
+func newTraceExporter(ctx context.Context, cfg *config.TraceExporterConfig) (sdkTrace.SpanExporter, error) {
 	var (
 		exporter sdkTrace.SpanExporter
 		err      error
 	)
 
-	switch cfg.ExporterConfig.Type {
+	switch cfg.Type {
 	case model.TraceExporterTypeStdout:
 		exporter, err = newStdOutExporter()
 	case model.TraceExporterTypeHTTP:
-		exporter, err = newOTLPTraceHTTPExporter(ctx, &cfg.ExporterConfig)
+		exporter, err = newOTLPTraceHTTPExporter(ctx, cfg)
+	case model.TraceExporterTypeFile:
+		exporter, err = newFileExporter(&cfg.File)
+	case model.TraceExporterTypeZipkin:
+		exporter, err = newZipkinExporter(cfg)
 	default:
 		err = ErrUnknownTraceExporterType
 	}
@@ -48,8 +81,87 @@ func newStdOutExporter() (*stdouttrace.Exporter, error) {
 	return exporter, nil
 }
 
+func newZipkinExporter(cfg *config.TraceExporterConfig) (*zipkin.Exporter, error) {
+	client := &http.Client{Timeout: cfg.Timeout}
+	if !cfg.Insecure {
+		tlsCfg, err := newTLSConfig(&cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zipkin exporter: %w", err)
+		}
+		if tlsCfg != nil {
+			transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // always a *http.Transport
+			transport.TLSClientConfig = tlsCfg
+			client.Transport = transport
+		}
+	}
+
+	if cfg.Compression == model.CompressionTypeGzip {
+		client.Transport = &gzipTransport{next: client.Transport}
+	}
+
+	exporter, err := zipkin.New(cfg.EndpointURL,
+		zipkin.WithClient(client),
+		zipkin.WithHeaders(cfg.Headers),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zipkin exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// gzipTransport compresses the request bodies, the zipkin exporter only sends uncompressed ones.
+type gzipTransport struct {
+	next http.RoundTripper
+}
+
+func (t *gzipTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return t.roundTripper().RoundTrip(r)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := io.Copy(zw, r.Body)
+	_ = r.Body.Close()
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+
+	r = r.Clone(r.Context())
+	r.Body = io.NopCloser(&buf)
+	r.ContentLength = int64(buf.Len())
+	r.GetBody = nil
+	r.Header.Set("Content-Encoding", "gzip")
+	return t.roundTripper().RoundTrip(r)
+}
+
+func (t *gzipTransport) roundTripper() http.RoundTripper {
+	if t.next == nil {
+		return http.DefaultTransport
+	}
+	return t.next
+}
+
 func newOTLPTraceHTTPExporter(ctx context.Context, cfg *config.TraceExporterConfig) (*otlptrace.Exporter, error) {
-	exporter, err := otlptracehttp.New(ctx,
+	opts, err := otlpTraceHTTPOptions(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlptracehttp exporter: %w", err)
+	}
+	client := otlptracehttp.NewClient(opts...)
+	if cfg.Queue.Enabled {
+		client = newPersistentClient(client, &cfg.Queue)
+	}
+	exporter, err := otlptrace.New(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlptracehttp exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+func otlpTraceHTTPOptions(cfg *config.TraceExporterConfig) ([]otlptracehttp.Option, error) {
+	opts := []otlptracehttp.Option{
 		otlptracehttp.WithRetry(otlptracehttp.RetryConfig{
 			Enabled:         cfg.RetryConfig.Enabled,
 			InitialInterval: cfg.RetryConfig.InitialInterval,
@@ -58,9 +170,25 @@ func newOTLPTraceHTTPExporter(ctx context.Context, cfg *config.TraceExporterConf
 		}),
 		otlptracehttp.WithTimeout(cfg.Timeout),
 		otlptracehttp.WithEndpointURL(cfg.EndpointURL),
-	)
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == model.CompressionTypeGzip {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+
+	// WithEndpointURL derives the transport security from the URL scheme, so the
+	// explicit settings must come after it to take precedence.
+	if cfg.Insecure {
+		return append(opts, otlptracehttp.WithInsecure()), nil
+	}
+	tlsCfg, err := newTLSConfig(&cfg.TLS)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create otlptracehttp exporter: %w", err)
+		return nil, err
 	}
-	return exporter, nil
+	if tlsCfg != nil {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsCfg))
+	}
+	return opts, nil
 }
@@ -0,0 +1,302 @@
+package oteltracer_test
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	collectorTrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+func TestNewTraceExporter_UnknownType(t *testing.T) {
+	t.Parallel()
+	_, err := oteltracer.NewTraceExporter(context.Background(), &config.TracingConfig{
+		ExporterConfig: config.TraceExporterConfig{Type: model.TraceExporterType(-1)},
+	})
+	require.ErrorIs(t, err, oteltracer.ErrUnknownTraceExporterType)
+}
+
+type zipkinSpan struct {
+	TraceID       string            `json:"traceId"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind"`
+	LocalEndpoint map[string]string `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags"`
+}
+
+func TestNewTraceExporter_Zipkin(t *testing.T) {
+	t.Parallel()
+	received := make(chan []zipkinSpan, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/spans", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var spans []zipkinSpan
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&spans))
+		received <- spans
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	exporterCfg := config.TraceExporterConfig{
+		Type:        model.TraceExporterTypeZipkin,
+		Processor:   model.SpanProcessorTypeSimple,
+		EndpointURL: server.URL + "/api/v2/spans",
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+	}
+	exporter, err := oteltracer.NewTraceExporter(context.Background(), &config.TracingConfig{ExporterConfig: exporterCfg})
+	require.NoError(t, err)
+	tp := newTestProvider(t, exporterCfg, exporter)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "lookup")
+	span.SetStatus(codes.Error, "not found")
+	span.End()
+
+	spans := <-received
+	require.Len(t, spans, 1)
+	assert.Equal(t, "lookup", spans[0].Name)
+	assert.Len(t, spans[0].TraceID, 32)
+	assert.Equal(t, "test", spans[0].LocalEndpoint["serviceName"])
+	assert.Equal(t, "not found", spans[0].Tags["error"])
+}
+
+// writePEM writes the PEM block to a file of the test directory and returns its path.
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+// newClientCert creates a self-signed client certificate and returns its pool and the paths of its PEM files.
+func newClientCert(t *testing.T) (pool *x509.CertPool, certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "exporter"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+	return pool, writePEM(t, "client.pem", "CERTIFICATE", der), writePEM(t, "client-key.pem", "PRIVATE KEY", keyDER)
+}
+
+// newCollector starts a collector receiving OTLP/HTTP spans and returns the span names of every request.
+func newCollector(t *testing.T, tlsCfg *tls.Config, secure bool) (*httptest.Server, chan []string) {
+	t.Helper()
+	received := make(chan []string, 1)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		body, err := gzip.NewReader(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		payload, err := io.ReadAll(body)
+		assert.NoError(t, err)
+		req := &collectorTrace.ExportTraceServiceRequest{}
+		assert.NoError(t, proto.Unmarshal(payload, req))
+		var names []string
+		for _, rs := range req.GetResourceSpans() {
+			for _, ss := range rs.GetScopeSpans() {
+				for _, s := range ss.GetSpans() {
+					names = append(names, s.GetName())
+				}
+			}
+		}
+		received <- names
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	if secure {
+		server.TLS = tlsCfg
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func exportSpan(t *testing.T, exporterCfg *config.TraceExporterConfig) error {
+	t.Helper()
+	exporter, err := oteltracer.NewTraceExporter(context.Background(), &config.TracingConfig{ExporterConfig: *exporterCfg})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = exporter.Shutdown(context.Background()) })
+	return exporter.ExportSpans(context.Background(), tracetest.SpanStubs{{Name: "lookup"}}.Snapshots())
+}
+
+func TestNewTraceExporter_HTTPTransport(t *testing.T) {
+	t.Parallel()
+	clientCAs, certFile, keyFile := newClientCert(t)
+	mTLS := &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+
+	tests := []struct {
+		name      string
+		serverTLS *tls.Config
+		secure    bool
+		tls       func(server *httptest.Server) config.TraceExporterTLSConfig
+		https     bool
+		insecure  bool
+		wantErr   bool
+	}{
+		{
+			name:   "ca file",
+			secure: true,
+			tls: func(server *httptest.Server) config.TraceExporterTLSConfig {
+				return config.TraceExporterTLSConfig{CAFile: writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)}
+			},
+		},
+		{
+			name:      "mtls",
+			serverTLS: mTLS,
+			secure:    true,
+			tls: func(server *httptest.Server) config.TraceExporterTLSConfig {
+				return config.TraceExporterTLSConfig{
+					CAFile:   writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw),
+					CertFile: certFile,
+					KeyFile:  keyFile,
+				}
+			},
+		},
+		{
+			name:      "mtls without client certificate",
+			serverTLS: mTLS,
+			secure:    true,
+			tls: func(server *httptest.Server) config.TraceExporterTLSConfig {
+				return config.TraceExporterTLSConfig{CAFile: writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)}
+			},
+			wantErr: true,
+		},
+		{
+			name:   "insecure skip verify",
+			secure: true,
+			tls: func(*httptest.Server) config.TraceExporterTLSConfig {
+				return config.TraceExporterTLSConfig{InsecureSkipVerify: true}
+			},
+		},
+		{
+			name:    "unknown certificate authority",
+			secure:  true,
+			tls:     func(*httptest.Server) config.TraceExporterTLSConfig { return config.TraceExporterTLSConfig{} },
+			wantErr: true,
+		},
+		{
+			// the https scheme of the endpoint is overridden by Insecure
+			name:     "insecure",
+			https:    true,
+			insecure: true,
+			tls:      func(*httptest.Server) config.TraceExporterTLSConfig { return config.TraceExporterTLSConfig{} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server, received := newCollector(t, tt.serverTLS, tt.secure)
+			endpoint := server.URL + "/v1/traces"
+			if tt.https {
+				endpoint = "https" + strings.TrimPrefix(endpoint, "http")
+			}
+			err := exportSpan(t, &config.TraceExporterConfig{
+				Type:        model.TraceExporterTypeHTTP,
+				EndpointURL: endpoint,
+				Headers:     map[string]string{"Authorization": "Bearer token"},
+				Compression: model.CompressionTypeGzip,
+				TLS:         tt.tls(server),
+				Insecure:    tt.insecure,
+			})
+			if tt.wantErr {
+				require.ErrorContains(t, err, "certificate")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"lookup"}, <-received)
+		})
+	}
+}
+
+func TestNewTraceExporter_InvalidTLS(t *testing.T) {
+	t.Parallel()
+	invalid := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a certificate"), 0o600))
+
+	for _, exporterType := range []model.TraceExporterType{model.TraceExporterTypeHTTP, model.TraceExporterTypeZipkin} {
+		newExporter := func(tlsCfg config.TraceExporterTLSConfig) error {
+			_, err := oteltracer.NewTraceExporter(context.Background(), &config.TracingConfig{
+				ExporterConfig: config.TraceExporterConfig{
+					Type:        exporterType,
+					EndpointURL: "https://localhost:4318/v1/traces",
+					TLS:         tlsCfg,
+				},
+			})
+			return err
+		}
+		require.ErrorIs(t, newExporter(config.TraceExporterTLSConfig{CAFile: invalid}),
+			oteltracer.ErrInvalidCACertificate, exporterType.String())
+		require.ErrorIs(t, newExporter(config.TraceExporterTLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}),
+			os.ErrNotExist, exporterType.String())
+		require.ErrorIs(t, newExporter(config.TraceExporterTLSConfig{CertFile: "client.pem"}),
+			oteltracer.ErrIncompleteClientCert, exporterType.String())
+	}
+}
+
+func TestNewTraceExporter_ZipkinCompression(t *testing.T) {
+	t.Parallel()
+	received := make(chan []zipkinSpan, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		body, err := gzip.NewReader(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		var spans []zipkinSpan
+		assert.NoError(t, json.NewDecoder(body).Decode(&spans))
+		received <- spans
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	require.NoError(t, exportSpan(t, &config.TraceExporterConfig{
+		Type:        model.TraceExporterTypeZipkin,
+		EndpointURL: server.URL + "/api/v2/spans",
+		Compression: model.CompressionTypeGzip,
+	}))
+	spans := <-received
+	require.Len(t, spans, 1)
+	assert.Equal(t, "lookup", spans[0].Name)
+}
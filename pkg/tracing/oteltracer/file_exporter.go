@@ -0,0 +1,216 @@
+package oteltracer
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"go.opentelemetry.io/otel"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	filePermissions      = 0o600
+	dirPermissions       = 0o750
+	rotatedFileLayout    = "20060102T150405.000000000"
+	compressedFileExt    = ".gz"
+	defaultTraceFileName = "traces.jsonl"
+)
+
+var ErrFileExporterShutdown = errors.New("file exporter is shut down")
+
+// fileExporter writes every exported batch as one line of OTLP/JSON encoded TracesData,
+// the format read by the collector otlpjsonfile receiver.
+// The file is rotated once it grows past the configured size.
+type fileExporter struct {
+	cfg  config.TraceExporterFileConfig
+	path string
+
+	mu sync.Mutex
+	// file is nil after a rotation failed to open the new file, which is retried by the next export
+	file   *os.File
+	size   int64
+	closed bool
+
+	// archiveMu serializes the compression and removal of the rotated files, done outside mu
+	archiveMu sync.Mutex
+}
+
+func newFileExporter(cfg *config.TraceExporterFileConfig) (*fileExporter, error) {
+	path := cfg.Path
+	if path == "" {
+		path = defaultTraceFileName
+	}
+	if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create trace file directory: %w", err)
+	}
+	e := &fileExporter{cfg: *cfg, path: path}
+	if err := e.open(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ExportSpans appends the spans to the file as a single line.
+func (e *fileExporter) ExportSpans(_ context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	line, err := json.Marshal(toOTLPTracesData(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	line = append(line, '\n')
+
+	e.mu.Lock()
+	rotated, err := e.write(line)
+	e.mu.Unlock()
+
+	if rotated != "" {
+		if archiveErr := e.archive(rotated); archiveErr != nil {
+			otel.Handle(archiveErr)
+		}
+	}
+	return err
+}
+
+// write appends line to the file, rotating it first if it would grow past MaxSize.
+// It returns the path the file was rotated to, if any.
+func (e *fileExporter) write(line []byte) (string, error) {
+	if e.closed {
+		return "", ErrFileExporterShutdown
+	}
+	if e.file == nil {
+		if err := e.open(); err != nil {
+			return "", err
+		}
+	}
+	var rotated string
+	if e.cfg.MaxSize > 0 && e.size > 0 && e.size+int64(len(line)) > e.cfg.MaxSize {
+		var err error
+		rotated, err = e.rotate()
+		if e.file == nil {
+			return rotated, err
+		}
+		// a failed rotation must not lose the spans, they are appended to whichever file is open
+		if err != nil {
+			otel.Handle(err)
+		}
+	}
+	n, err := e.file.Write(line)
+	e.size += int64(n)
+	if err != nil {
+		return rotated, fmt.Errorf("failed to write spans: %w", err)
+	}
+	return rotated, nil
+}
+
+// Shutdown syncs and closes the file, and waits for the rotated files being compressed.
+func (e *fileExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	var err error
+	if e.file != nil {
+		err = errors.Join(e.file.Sync(), e.file.Close())
+	}
+	e.closed = true
+	e.file = nil
+	e.mu.Unlock()
+
+	e.archiveMu.Lock()
+	defer e.archiveMu.Unlock()
+	return err
+}
+
+func (e *fileExporter) open() error {
+	file, err := os.OpenFile(e.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePermissions)
+	if err != nil {
+		return fmt.Errorf("failed to open trace file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat trace file: %w", err)
+	}
+	e.file = file
+	e.size = info.Size()
+	return nil
+}
+
+// rotate moves the current file aside with a timestamp suffix and opens a new file.
+// It returns the path of the rotated file, empty when it could not be moved.
+func (e *fileExporter) rotate() (string, error) {
+	err := e.file.Close()
+	e.file = nil
+	if err != nil {
+		return "", errors.Join(fmt.Errorf("failed to close trace file: %w", err), e.open())
+	}
+	ext := filepath.Ext(e.path)
+	rotated := strings.TrimSuffix(e.path, ext) + "-" + time.Now().UTC().Format(rotatedFileLayout) + ext
+	if err := os.Rename(e.path, rotated); err != nil {
+		return "", errors.Join(fmt.Errorf("failed to rotate trace file: %w", err), e.open())
+	}
+	return rotated, e.open()
+}
+
+// archive compresses the rotated file if configured and removes the backups over MaxBackups.
+func (e *fileExporter) archive(rotated string) error {
+	e.archiveMu.Lock()
+	defer e.archiveMu.Unlock()
+
+	if e.cfg.Compress {
+		if err := compressFile(rotated); err != nil {
+			return err
+		}
+	}
+	return e.removeOldBackups()
+}
+
+func (e *fileExporter) removeOldBackups() error {
+	if e.cfg.MaxBackups <= 0 {
+		return nil
+	}
+	ext := filepath.Ext(e.path)
+	backups, err := filepath.Glob(strings.TrimSuffix(e.path, ext) + "-*" + ext + "*")
+	if err != nil {
+		return fmt.Errorf("failed to list trace file backups: %w", err)
+	}
+	if len(backups) <= e.cfg.MaxBackups {
+		return nil
+	}
+	// the timestamp layout sorts lexically in chronological order
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-e.cfg.MaxBackups] {
+		if err := os.Remove(backup); err != nil {
+			return fmt.Errorf("failed to remove trace file backup: %w", err)
+		}
+	}
+	return nil
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open rotated trace file: %w", err)
+	}
+	dst, err := os.OpenFile(path+compressedFileExt, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, filePermissions)
+	if err != nil {
+		_ = src.Close()
+		return fmt.Errorf("failed to create compressed trace file: %w", err)
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if err = errors.Join(err, zw.Close(), dst.Close(), src.Close()); err != nil {
+		return fmt.Errorf("failed to compress trace file: %w", err)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,114 @@
+package oteltracer_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+func newFileTraceProvider(t *testing.T, fileCfg config.TraceExporterFileConfig) *sdkTrace.TracerProvider {
+	t.Helper()
+	exporterCfg := config.TraceExporterConfig{
+		Type:      model.TraceExporterTypeFile,
+		Processor: model.SpanProcessorTypeSimple,
+		File:      fileCfg,
+	}
+	exporter, err := oteltracer.NewTraceExporter(context.Background(), &config.TracingConfig{ExporterConfig: exporterCfg})
+	require.NoError(t, err)
+	return newTestProvider(t, exporterCfg, exporter)
+}
+
+func TestNewTraceExporter_FileWritesOTLPJSONLines(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	tp := newFileTraceProvider(t, config.TraceExporterFileConfig{Path: path})
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	_, child := tp.Tracer("test").Start(ctx, "child")
+	child.SetAttributes(attribute.Int64("rows", 3))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 2)
+
+	span := lines[0]["resourceSpans"].([]any)[0].(map[string]any)["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)[0].(map[string]any)
+	assert.Equal(t, "child", span["name"])
+	assert.Len(t, span["traceId"], 32)
+	assert.Len(t, span["parentSpanId"], 16)
+	assert.Equal(t, map[string]any{"code": float64(2), "message": "boom"}, span["status"])
+	assert.Equal(t, []any{map[string]any{"key": "rows", "value": map[string]any{"intValue": "3"}}}, span["attributes"])
+}
+
+func TestNewTraceExporter_FileRotation(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	tp := newFileTraceProvider(t, config.TraceExporterFileConfig{
+		Path:       filepath.Join(dir, "traces.jsonl"),
+		MaxSize:    1,
+		MaxBackups: 2,
+		Compress:   true,
+	})
+
+	for i := 0; i < 5; i++ {
+		_, span := tp.Tracer("test").Start(context.Background(), "span")
+		span.End()
+	}
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	backups, err := filepath.Glob(filepath.Join(dir, "traces-*.jsonl.gz"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+	assert.FileExists(t, filepath.Join(dir, "traces.jsonl"))
+}
+
+func TestNewTraceExporter_FileReopenedAfterFailedRotation(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "traces")
+	path := filepath.Join(dir, "traces.jsonl")
+	tp := newFileTraceProvider(t, config.TraceExporterFileConfig{Path: path, MaxSize: 1})
+	end := func(name string) {
+		_, span := tp.Tracer("test").Start(context.Background(), name)
+		span.End()
+	}
+
+	end("first")
+	// the rotation can neither move the file nor open a new one
+	require.NoError(t, os.RemoveAll(dir))
+	end("lost")
+
+	// the file is opened again by the next export
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	end("second")
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"name":"second"`)
+	assert.NotContains(t, string(content), `"name":"lost"`)
+}
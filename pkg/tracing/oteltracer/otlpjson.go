@@ -0,0 +1,241 @@
+package oteltracer
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// The types below follow the OTLP/JSON encoding of TracesData, as written by the
+// collector file exporter: hex encoded ids, integer enums and 64-bit integers as strings.
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding.
+
+type otlpTracesData struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	SchemaURL  string           `json:"schemaUrl,omitempty"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope     otlpScope  `json:"scope"`
+	Spans     []otlpSpan `json:"spans"`
+	SchemaURL string     `json:"schemaUrl,omitempty"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID                string         `json:"traceId"`
+	SpanID                 string         `json:"spanId"`
+	TraceState             string         `json:"traceState,omitempty"`
+	ParentSpanID           string         `json:"parentSpanId,omitempty"`
+	Flags                  uint32         `json:"flags,omitempty"`
+	Name                   string         `json:"name"`
+	Kind                   int            `json:"kind,omitempty"`
+	StartTimeUnixNano      uint64         `json:"startTimeUnixNano,string"`
+	EndTimeUnixNano        uint64         `json:"endTimeUnixNano,string"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+	Events                 []otlpEvent    `json:"events,omitempty"`
+	DroppedEventsCount     int            `json:"droppedEventsCount,omitempty"`
+	Links                  []otlpLink     `json:"links,omitempty"`
+	DroppedLinksCount      int            `json:"droppedLinksCount,omitempty"`
+	Status                 otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano           uint64         `json:"timeUnixNano,string"`
+	Name                   string         `json:"name"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+}
+
+type otlpLink struct {
+	TraceID                string         `json:"traceId"`
+	SpanID                 string         `json:"spanId"`
+	TraceState             string         `json:"traceState,omitempty"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+	Flags                  uint32         `json:"flags,omitempty"`
+}
+
+type otlpStatus struct {
+	Message string `json:"message,omitempty"`
+	Code    int    `json:"code,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *int64          `json:"intValue,string,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+// OTLP status codes, the numbering differs from the codes package.
+const (
+	otlpStatusCodeOk    = 1
+	otlpStatusCodeError = 2
+)
+
+// otlpSampledFlag is the W3C sampled flag, the lower 8 bits of the OTLP span flags.
+const otlpSampledFlag = 0x01
+
+// toOTLPTracesData groups the spans by resource and instrumentation scope, keeping their order.
+func toOTLPTracesData(spans []sdkTrace.ReadOnlySpan) otlpTracesData {
+	type scopeKey struct {
+		resource *resource.Resource
+		scope    instrumentation.Scope
+	}
+	var (
+		data          otlpTracesData
+		resourceIndex = make(map[*resource.Resource]int)
+		scopeIndex    = make(map[scopeKey]int)
+	)
+
+	for _, s := range spans {
+		res := s.Resource()
+		ri, ok := resourceIndex[res]
+		if !ok {
+			ri = len(data.ResourceSpans)
+			resourceIndex[res] = ri
+			data.ResourceSpans = append(data.ResourceSpans, otlpResourceSpans{
+				Resource:  otlpResource{Attributes: toOTLPAttributes(res.Attributes())},
+				SchemaURL: res.SchemaURL(),
+			})
+		}
+
+		key := scopeKey{resource: res, scope: s.InstrumentationScope()}
+		si, ok := scopeIndex[key]
+		if !ok {
+			si = len(data.ResourceSpans[ri].ScopeSpans)
+			scopeIndex[key] = si
+			data.ResourceSpans[ri].ScopeSpans = append(data.ResourceSpans[ri].ScopeSpans, otlpScopeSpans{
+				Scope:     otlpScope{Name: key.scope.Name, Version: key.scope.Version},
+				SchemaURL: key.scope.SchemaURL,
+			})
+		}
+
+		scopeSpans := &data.ResourceSpans[ri].ScopeSpans[si]
+		scopeSpans.Spans = append(scopeSpans.Spans, toOTLPSpan(s))
+	}
+	return data
+}
+
+func toOTLPSpan(s sdkTrace.ReadOnlySpan) otlpSpan {
+	sc := s.SpanContext()
+	span := otlpSpan{
+		TraceID:                sc.TraceID().String(),
+		SpanID:                 sc.SpanID().String(),
+		TraceState:             sc.TraceState().String(),
+		Flags:                  uint32(sc.TraceFlags() & otlpSampledFlag),
+		Name:                   s.Name(),
+		Kind:                   int(s.SpanKind()),
+		StartTimeUnixNano:      uint64(s.StartTime().UnixNano()),
+		EndTimeUnixNano:        uint64(s.EndTime().UnixNano()),
+		Attributes:             toOTLPAttributes(s.Attributes()),
+		DroppedAttributesCount: s.DroppedAttributes(),
+		DroppedEventsCount:     s.DroppedEvents(),
+		DroppedLinksCount:      s.DroppedLinks(),
+		Status:                 toOTLPStatus(s.Status()),
+	}
+	if s.Parent().SpanID().IsValid() {
+		span.ParentSpanID = s.Parent().SpanID().String()
+	}
+	for _, e := range s.Events() {
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano:           uint64(e.Time.UnixNano()),
+			Name:                   e.Name,
+			Attributes:             toOTLPAttributes(e.Attributes),
+			DroppedAttributesCount: e.DroppedAttributeCount,
+		})
+	}
+	for _, l := range s.Links() {
+		span.Links = append(span.Links, otlpLink{
+			TraceID:                l.SpanContext.TraceID().String(),
+			SpanID:                 l.SpanContext.SpanID().String(),
+			TraceState:             l.SpanContext.TraceState().String(),
+			Attributes:             toOTLPAttributes(l.Attributes),
+			DroppedAttributesCount: l.DroppedAttributeCount,
+			Flags:                  uint32(l.SpanContext.TraceFlags() & otlpSampledFlag),
+		})
+	}
+	return span
+}
+
+func toOTLPStatus(status sdkTrace.Status) otlpStatus {
+	switch status.Code {
+	case codes.Error:
+		return otlpStatus{Code: otlpStatusCodeError, Message: status.Description}
+	case codes.Ok:
+		return otlpStatus{Code: otlpStatusCodeOk}
+	default:
+		return otlpStatus{}
+	}
+}
+
+func toOTLPAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	kvs := make([]otlpKeyValue, len(attrs))
+	for i, kv := range attrs {
+		kvs[i] = otlpKeyValue{Key: string(kv.Key), Value: toOTLPValue(kv.Value)}
+	}
+	return kvs
+}
+
+func toOTLPValue(v attribute.Value) otlpAnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return otlpAnyValue{BoolValue: &b}
+	case attribute.INT64:
+		i := v.AsInt64()
+		return otlpAnyValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return otlpAnyValue{DoubleValue: &f}
+	case attribute.BOOLSLICE:
+		return toOTLPArray(v.AsBoolSlice(), attribute.BoolValue)
+	case attribute.INT64SLICE:
+		return toOTLPArray(v.AsInt64Slice(), attribute.Int64Value)
+	case attribute.FLOAT64SLICE:
+		return toOTLPArray(v.AsFloat64Slice(), attribute.Float64Value)
+	case attribute.STRINGSLICE:
+		return toOTLPArray(v.AsStringSlice(), attribute.StringValue)
+	default:
+		s := v.Emit()
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+func toOTLPArray[T any](values []T, toValue func(T) attribute.Value) otlpAnyValue {
+	array := &otlpArrayValue{Values: make([]otlpAnyValue, len(values))}
+	for i, v := range values {
+		array.Values[i] = toOTLPValue(toValue(v))
+	}
+	return otlpAnyValue{ArrayValue: array}
+}
@@ -0,0 +1,197 @@
+package oteltracer
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Defaults used by the batch span processor when the config leaves a setting unset.
+// They match the defaults of the OpenTelemetry SDK.
+const (
+	defaultBatchTimeout       = 5 * time.Second
+	defaultExportTimeout      = 30 * time.Second
+	defaultMaxQueueSize       = 2048
+	defaultMaxExportBatchSize = 512
+)
+
+// ExportStats holds the span counters of the processors created by NewTraceProvider.
+// It is safe for concurrent use, pass it to the provider using WithExportStats.
+// Its counters are summed over all the exporters, Exporter returns those of a single one.
+type ExportStats struct {
+	exported atomic.Uint64
+	failed   atomic.Uint64
+	dropped  atomic.Uint64
+
+	parent    *ExportStats
+	mu        sync.Mutex
+	exporters []*ExportStats
+}
+
+// Exported returns the number of spans successfully handed to the exporter.
+func (s *ExportStats) Exported() uint64 {
+	return s.exported.Load()
+}
+
+// Failed returns the number of spans the exporter returned an error for.
+func (s *ExportStats) Failed() uint64 {
+	return s.failed.Load()
+}
+
+// Dropped returns the number of spans discarded because the batch queue was full.
+func (s *ExportStats) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Exporter returns the counters of a single exporter. Index 0 is the exporter of
+// TracingConfig.ExporterConfig, index i the one of TracingConfig.AdditionalExporters[i-1].
+func (s *ExportStats) Exporter(index int) *ExportStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.exporters) <= index {
+		s.exporters = append(s.exporters, &ExportStats{parent: s})
+	}
+	return s.exporters[index]
+}
+
+// addExported counts n exported spans in s and in the stats it belongs to,
+// like addFailed and addDropped for their counters.
+func (s *ExportStats) addExported(n uint64) {
+	for stats := s; stats != nil; stats = stats.parent {
+		stats.exported.Add(n)
+	}
+}
+
+func (s *ExportStats) addFailed(n uint64) {
+	for stats := s; stats != nil; stats = stats.parent {
+		stats.failed.Add(n)
+	}
+}
+
+func (s *ExportStats) addDropped(n uint64) {
+	for stats := s; stats != nil; stats = stats.parent {
+		stats.dropped.Add(n)
+	}
+}
+
+// newSpanProcessor creates the span processor configured by cfg, exporting to exporter.
+//
+//nolint:ireturn // the processor type depends on the configuration
+func newSpanProcessor(cfg *config.TraceExporterConfig, exporter sdkTrace.SpanExporter, stats *ExportStats) sdkTrace.SpanProcessor {
+	var processor sdkTrace.SpanProcessor
+	exporter = &statsExporter{SpanExporter: exporter, stats: stats}
+	if cfg.Processor == model.SpanProcessorTypeSimple {
+		processor = sdkTrace.NewSimpleSpanProcessor(exporter)
+	} else {
+		processor = newBatchSpanProcessor(cfg, exporter, stats)
+	}
+
+	if cfg.Filter == (config.TraceExporterFilterConfig{}) {
+		return processor
+	}
+	return &filterSpanProcessor{SpanProcessor: processor, filter: cfg.Filter}
+}
+
+// filterSpanProcessor forwards to the wrapped processor only the ended spans matching the filter.
+type filterSpanProcessor struct {
+	sdkTrace.SpanProcessor
+	filter config.TraceExporterFilterConfig
+}
+
+func (p *filterSpanProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	if p.filter.ErrorsOnly && s.Status().Code != codes.Error {
+		return
+	}
+	if s.EndTime().Sub(s.StartTime()) < p.filter.MinDuration {
+		return
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// statsExporter counts the outcome of every export in ExportStats.
+type statsExporter struct {
+	sdkTrace.SpanExporter
+	stats *ExportStats
+}
+
+func (e *statsExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err != nil {
+		e.stats.addFailed(uint64(len(spans)))
+		return err
+	}
+	e.stats.addExported(uint64(len(spans)))
+	return nil
+}
+
+// batchSpanProcessor wraps the batch span processor of the SDK to count the spans it would drop
+// when its queue is full, which the SDK does not expose. Unless BlockOnQueueFull is set, the spans
+// ended while maxQueueSize spans wait for their export are dropped and counted before reaching the SDK
+// processor, whose queue is never full as a result.
+type batchSpanProcessor struct {
+	sdkTrace.SpanProcessor
+	stats        *ExportStats
+	maxQueueSize int64
+	blocking     bool
+	pending      atomic.Int64
+}
+
+//nolint:ireturn // the SDK processor is not exported
+func newBatchSpanProcessor(
+	cfg *config.TraceExporterConfig,
+	exporter sdkTrace.SpanExporter,
+	stats *ExportStats,
+) sdkTrace.SpanProcessor {
+	maxQueueSize := valueOrDefault(cfg.MaxQueueSize, defaultMaxQueueSize)
+	opts := []sdkTrace.BatchSpanProcessorOption{
+		sdkTrace.WithBatchTimeout(valueOrDefault(cfg.BatchTimeout, defaultBatchTimeout)),
+		sdkTrace.WithExportTimeout(valueOrDefault(cfg.ExportTimeout, defaultExportTimeout)),
+		sdkTrace.WithMaxQueueSize(maxQueueSize),
+		sdkTrace.WithMaxExportBatchSize(valueOrDefault(cfg.MaxExportBatchSize, defaultMaxExportBatchSize)),
+	}
+	if cfg.BlockOnQueueFull {
+		return sdkTrace.NewBatchSpanProcessor(exporter, append(opts, sdkTrace.WithBlocking())...)
+	}
+	p := &batchSpanProcessor{stats: stats, maxQueueSize: int64(maxQueueSize)}
+	p.SpanProcessor = sdkTrace.NewBatchSpanProcessor(&pendingExporter{SpanExporter: exporter, processor: p}, opts...)
+	return p
+}
+
+func valueOrDefault[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
+	return value
+}
+
+// OnEnd queues the span for export, or drops it when the queue is full.
+func (p *batchSpanProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	if p.pending.Add(1) > p.maxQueueSize {
+		p.pending.Add(-1)
+		p.stats.addDropped(1)
+		return
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// pendingExporter releases the queue slots of the spans of every export.
+type pendingExporter struct {
+	sdkTrace.SpanExporter
+	processor *batchSpanProcessor
+}
+
+func (e *pendingExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	e.processor.pending.Add(-int64(len(spans)))
+	return e.SpanExporter.ExportSpans(ctx, spans)
+}
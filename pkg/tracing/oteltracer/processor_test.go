@@ -0,0 +1,120 @@
+package oteltracer_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+// blockingExporter holds every export until release is closed.
+type blockingExporter struct {
+	*tracetest.InMemoryExporter
+	release chan struct{}
+}
+
+func (e *blockingExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	<-e.release
+	return e.InMemoryExporter.ExportSpans(ctx, spans)
+}
+
+func newTestProvider(
+	t *testing.T,
+	exporterCfg config.TraceExporterConfig,
+	exporter sdkTrace.SpanExporter,
+	opts ...oteltracer.ProviderOption,
+) *sdkTrace.TracerProvider {
+	t.Helper()
+	tp, err := oteltracer.NewTraceProvider(&config.TracingConfig{
+		Enabled:        true,
+		ExporterConfig: exporterCfg,
+	}, exporter, "test", opts...)
+	require.NoError(t, err)
+	sdkTP, ok := tp.(*sdkTrace.TracerProvider)
+	require.True(t, ok)
+	t.Cleanup(func() { _ = sdkTP.Shutdown(context.Background()) })
+	return sdkTP
+}
+
+func TestNewTraceProvider_BatchProcessorExportsOnFlush(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	stats := &oteltracer.ExportStats{}
+	tp := newTestProvider(t, config.TraceExporterConfig{BatchTimeout: time.Hour}, exporter,
+		oteltracer.WithExportStats(stats))
+
+	for i := 0; i < 3; i++ {
+		_, span := tp.Tracer("test").Start(context.Background(), "span")
+		span.End()
+	}
+	assert.Empty(t, exporter.GetSpans())
+
+	require.NoError(t, tp.ForceFlush(context.Background()))
+	assert.Len(t, exporter.GetSpans(), 3)
+	assert.Equal(t, uint64(3), stats.Exported())
+	assert.Equal(t, uint64(0), stats.Dropped())
+}
+
+func TestNewTraceProvider_BatchProcessorCountsDroppedSpans(t *testing.T) {
+	t.Parallel()
+	exporter := &blockingExporter{InMemoryExporter: tracetest.NewInMemoryExporter(), release: make(chan struct{})}
+	stats := &oteltracer.ExportStats{}
+	tp := newTestProvider(t, config.TraceExporterConfig{
+		BatchTimeout:       time.Hour,
+		MaxQueueSize:       2,
+		MaxExportBatchSize: 1,
+	}, exporter, oteltracer.WithExportStats(stats))
+
+	for i := 0; i < 10; i++ {
+		_, span := tp.Tracer("test").Start(context.Background(), "span")
+		span.End()
+	}
+	close(exporter.release)
+	require.NoError(t, tp.ForceFlush(context.Background()))
+
+	assert.Equal(t, uint64(10), stats.Exported()+stats.Dropped())
+	assert.Positive(t, stats.Dropped())
+	assert.Len(t, exporter.GetSpans(), int(stats.Exported()))
+}
+
+func TestNewTraceProvider_SimpleProcessorExportsImmediately(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := newTestProvider(t, config.TraceExporterConfig{Processor: model.SpanProcessorTypeSimple}, exporter)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	span.End()
+
+	assert.Len(t, exporter.GetSpans(), 1)
+}
+
+func TestNewTraceProvider_BatchProcessorEndDuringShutdown(t *testing.T) {
+	t.Parallel()
+	for _, blocking := range []bool{false, true} {
+		exporter := tracetest.NewInMemoryExporter()
+		tp := newTestProvider(t, config.TraceExporterConfig{MaxQueueSize: 1, BlockOnQueueFull: blocking}, exporter)
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 100 {
+					_, span := tp.Tracer("test").Start(context.Background(), "span")
+					span.End()
+				}
+			}()
+		}
+		require.NoError(t, tp.Shutdown(context.Background()))
+		wg.Wait()
+	}
+}
@@ -1,8 +1,10 @@
 package oteltracer
 
 import (
+	"errors"
 	"fmt"
 	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
@@ -10,6 +12,61 @@ import (
 	"go.opentelemetry.io/otel/trace/noop"
 )
 
+var ErrAdditionalExportersMismatch = errors.New("additional exporters do not match the configuration")
+
+// ProviderOption configures optional behaviour of the trace provider created by NewTraceProvider.
+type ProviderOption func(*providerOptions)
+
+type providerOptions struct {
+	stats               *ExportStats
+	tailSamplingStats   *TailSamplingStats
+	additionalExporters []sdkTrace.SpanExporter
+	baggageKeys         []string
+	spanProcessors      []model.SpanProcessor
+}
+
+// WithExportStats makes the provider record its export counters in stats, summed over the
+// primary and additional exporters, and per exporter in ExportStats.Exporter.
+func WithExportStats(stats *ExportStats) ProviderOption {
+	return func(o *providerOptions) {
+		o.stats = stats
+	}
+}
+
+// WithTailSamplingStats makes the provider record the counters of its tail sampler in stats.
+// It is only used when TracingConfig.TailSampling is enabled.
+func WithTailSamplingStats(stats *TailSamplingStats) ProviderOption {
+	return func(o *providerOptions) {
+		o.tailSamplingStats = stats
+	}
+}
+
+// WithAdditionalExporters registers the exporters created by NewAdditionalTraceExporters.
+// Every exporter gets its own span processor, built from the entry of
+// TracingConfig.AdditionalExporters at the same index.
+func WithAdditionalExporters(exporters ...sdkTrace.SpanExporter) ProviderOption {
+	return func(o *providerOptions) {
+		o.additionalExporters = append(o.additionalExporters, exporters...)
+	}
+}
+
+// WithBaggageAttributes copies the baggage members with the given keys onto the attributes of
+// every started span, e.g. to search the spans of a tenant. The other members are ignored.
+func WithBaggageAttributes(keys ...string) ProviderOption {
+	return func(o *providerOptions) {
+		o.baggageKeys = append(o.baggageKeys, keys...)
+	}
+}
+
+// WithSpanProcessor registers processors enriching or filtering the spans before they are
+// exported. They are called in the order they are registered, after the baggage attributes
+// are copied, and a span dropped by one of them is exported by none of the exporters.
+func WithSpanProcessor(processors ...model.SpanProcessor) ProviderOption {
+	return func(o *providerOptions) {
+		o.spanProcessors = append(o.spanProcessors, processors...)
+	}
+}
+
 // NewTraceProvider creates a new trace provider using the exporter and configuration provided.
 // This provider is used to initialize the tracer which is then used across the application.
 //
@@ -18,7 +75,13 @@ func NewTraceProvider(
 	cfg *config.TracingConfig,
 	traceExporter sdkTrace.SpanExporter,
 	serviceName string,
+	opts ...ProviderOption,
 ) (trace.TracerProvider, error) {
+	options := providerOptions{stats: &ExportStats{}, tailSamplingStats: &TailSamplingStats{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	if !cfg.Enabled {
 		return noop.NewTracerProvider(), nil
 	}
@@ -32,10 +95,53 @@ func NewTraceProvider(
 		return nil, fmt.Errorf("failed creating resource info: %w", err)
 	}
 
+	if len(options.additionalExporters) != len(cfg.AdditionalExporters) {
+		return nil, fmt.Errorf("%w: %d configured, %d provided", ErrAdditionalExportersMismatch,
+			len(cfg.AdditionalExporters), len(options.additionalExporters))
+	}
+
+	if cfg.TailSampling.Enabled {
+		if err := validateTailSampling(&cfg.TailSampling); err != nil {
+			return nil, err
+		}
+	}
+
+	var processors []model.SpanProcessor
+	if len(options.baggageKeys) > 0 {
+		processors = append(processors, newBaggageSpanProcessor(options.baggageKeys))
+	}
+	processors = append(processors, options.spanProcessors...)
+
+	exporters := []sdkTrace.SpanProcessor{newSpanProcessor(&cfg.ExporterConfig, traceExporter, options.stats.Exporter(0))}
+	for i, exporter := range options.additionalExporters {
+		exporters = append(exporters, newSpanProcessor(&cfg.AdditionalExporters[i], exporter, options.stats.Exporter(i+1)))
+	}
+	if cfg.TailSampling.Enabled {
+		exporters = []sdkTrace.SpanProcessor{newTailSampler(&cfg.TailSampling, exporters, options.tailSamplingStats)}
+	}
+
 	tp := sdkTrace.NewTracerProvider(
 		sdkTrace.WithResource(r),
-		sdkTrace.WithBatcher(traceExporter,
-			sdkTrace.WithBatchTimeout(cfg.ExporterConfig.BatchTimeout)),
+		sdkTrace.WithRawSpanLimits(newSpanLimits(&cfg.SpanLimits)),
+		sdkTrace.WithSpanProcessor(newCompositeSpanProcessor(processors, exporters)),
 	)
 	return tp, nil
 }
+
+// newSpanLimits overrides the SDK default limits, which also read the OTEL_SPAN_* environment
+// variables, with the non-zero fields of cfg.
+func newSpanLimits(cfg *config.SpanLimitsConfig) sdkTrace.SpanLimits {
+	limits := sdkTrace.NewSpanLimits()
+	override := func(limit *int, value int) {
+		if value != 0 {
+			*limit = value
+		}
+	}
+	override(&limits.AttributeValueLengthLimit, cfg.AttributeValueLengthLimit)
+	override(&limits.AttributeCountLimit, cfg.AttributeCountLimit)
+	override(&limits.EventCountLimit, cfg.EventCountLimit)
+	override(&limits.LinkCountLimit, cfg.LinkCountLimit)
+	override(&limits.AttributePerEventCountLimit, cfg.AttributePerEventCountLimit)
+	override(&limits.AttributePerLinkCountLimit, cfg.AttributePerLinkCountLimit)
+	return limits
+}
@@ -0,0 +1,180 @@
+package oteltracer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+func TestNewTraceProvider_AdditionalExporters(t *testing.T) {
+	t.Parallel()
+	primary := tracetest.NewInMemoryExporter()
+	errorsOnly := tracetest.NewInMemoryExporter()
+	cfg := &config.TracingConfig{
+		Enabled:        true,
+		ExporterConfig: config.TraceExporterConfig{Processor: model.SpanProcessorTypeSimple},
+		AdditionalExporters: []config.TraceExporterConfig{{
+			Processor: model.SpanProcessorTypeSimple,
+			Filter:    config.TraceExporterFilterConfig{ErrorsOnly: true},
+		}},
+	}
+	stats := &oteltracer.ExportStats{}
+	tp, err := oteltracer.NewTraceProvider(cfg, primary, "test",
+		oteltracer.WithAdditionalExporters(errorsOnly), oteltracer.WithExportStats(stats))
+	require.NoError(t, err)
+
+	_, ok := tp.Tracer("test").Start(context.Background(), "ok")
+	ok.End()
+	_, failed := tp.Tracer("test").Start(context.Background(), "failed")
+	failed.SetStatus(codes.Error, "boom")
+	failed.End()
+
+	assert.Len(t, primary.GetSpans(), 2)
+	require.Len(t, errorsOnly.GetSpans(), 1)
+	assert.Equal(t, "failed", errorsOnly.GetSpans()[0].Name)
+	assert.Equal(t, uint64(2), stats.Exporter(0).Exported())
+	assert.Equal(t, uint64(1), stats.Exporter(1).Exported())
+	assert.Equal(t, uint64(3), stats.Exported())
+}
+
+func TestNewTraceProvider_AdditionalExportersMismatch(t *testing.T) {
+	t.Parallel()
+	cfg := &config.TracingConfig{
+		Enabled:             true,
+		AdditionalExporters: []config.TraceExporterConfig{{}, {}},
+	}
+	_, err := oteltracer.NewTraceProvider(cfg, tracetest.NewInMemoryExporter(), "test",
+		oteltracer.WithAdditionalExporters(tracetest.NewInMemoryExporter()))
+	require.ErrorIs(t, err, oteltracer.ErrAdditionalExportersMismatch)
+}
+
+func TestNewTraceProvider_BaggageAttributes(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := newTestProvider(t, config.TraceExporterConfig{Processor: model.SpanProcessorTypeSimple}, exporter,
+		oteltracer.WithBaggageAttributes("tenant.id", "region"))
+
+	ctx, err := model.SetBaggage(context.Background(), "tenant.id", "acme")
+	require.NoError(t, err)
+	ctx, err = model.SetBaggage(ctx, "user.id", "42")
+	require.NoError(t, err)
+	assert.Equal(t, []model.KeyValue{
+		model.NewKeyValue("tenant.id", "acme"),
+		model.NewKeyValue("user.id", "42"),
+	}, model.Baggage(ctx))
+
+	_, span := tp.Tracer("test").Start(ctx, "span")
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("tenant.id", "acme")}, spans[0].Attributes)
+}
+
+func TestSetBaggage_InvalidKey(t *testing.T) {
+	t.Parallel()
+	ctx, err := model.SetBaggage(context.Background(), "", "value")
+	require.Error(t, err)
+	assert.Empty(t, model.Baggage(ctx))
+}
+
+// healthCheckProcessor adds a region attribute to every span and drops the health checks.
+type healthCheckProcessor struct {
+	ended []model.ReadOnlySpan
+}
+
+func (p *healthCheckProcessor) OnStart(_ context.Context, span model.Span) {
+	span.SetAttributes(model.NewKeyValue("region", "eu-west-1"))
+}
+
+func (p *healthCheckProcessor) OnEnd(span model.ReadOnlySpan) bool {
+	p.ended = append(p.ended, span)
+	return span.Name() != "GET /healthz"
+}
+
+func TestNewTraceProvider_SpanProcessor(t *testing.T) {
+	t.Parallel()
+	primary := tracetest.NewInMemoryExporter()
+	additional := tracetest.NewInMemoryExporter()
+	processor := &healthCheckProcessor{}
+	cfg := &config.TracingConfig{
+		Enabled:             true,
+		ExporterConfig:      config.TraceExporterConfig{Processor: model.SpanProcessorTypeSimple},
+		AdditionalExporters: []config.TraceExporterConfig{{Processor: model.SpanProcessorTypeSimple}},
+	}
+	tp, err := oteltracer.NewTraceProvider(cfg, primary, "test",
+		oteltracer.WithAdditionalExporters(additional),
+		oteltracer.WithSpanProcessor(processor))
+	require.NoError(t, err)
+	tracer := oteltracer.NewTracer(cfg, tp)
+
+	ctx, parent := tracer.StartSpan(context.Background(), "GET /orders", model.WithSpanKind(model.SpanKindServer))
+	_, health := tracer.StartSpan(ctx, "GET /healthz")
+	health.End()
+	parent.SetStatus(model.StatusCodeError, "boom")
+	parent.End()
+
+	for _, exporter := range []*tracetest.InMemoryExporter{primary, additional} {
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "GET /orders", spans[0].Name)
+		assert.Equal(t, []attribute.KeyValue{attribute.String("region", "eu-west-1")}, spans[0].Attributes)
+	}
+
+	require.Len(t, processor.ended, 2)
+	child, root := processor.ended[0], processor.ended[1]
+	assert.Equal(t, root.TraceID(), child.TraceID())
+	assert.Equal(t, root.SpanID(), child.ParentSpanID())
+	assert.Empty(t, root.ParentSpanID())
+	assert.Equal(t, model.SpanKindServer, root.SpanKind())
+	assert.False(t, root.EndTime().Before(root.StartTime()))
+	assert.Equal(t, []model.KeyValue{model.NewKeyValue("region", "eu-west-1")}, root.Attributes())
+	code, description := root.Status()
+	assert.Equal(t, model.StatusCodeError, code)
+	assert.Equal(t, "boom", description)
+}
+
+func TestNewTraceProvider_SpanLimits(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	cfg := &config.TracingConfig{
+		Enabled:        true,
+		ExporterConfig: config.TraceExporterConfig{Processor: model.SpanProcessorTypeSimple},
+		SpanLimits: config.SpanLimitsConfig{
+			AttributeValueLengthLimit: 6,
+			AttributeCountLimit:       2,
+			EventCountLimit:           1,
+		},
+	}
+	tp, err := oteltracer.NewTraceProvider(cfg, exporter, "test")
+	require.NoError(t, err)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	span.SetAttributes(
+		attribute.String("db.statement", "SELECT * FROM users"),
+		attribute.Int("a", 1),
+		attribute.Int("b", 2),
+	)
+	span.AddEvent("first")
+	span.AddEvent("second")
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("db.statement", "SELECT"),
+		attribute.Int("a", 1),
+	}, spans[0].Attributes)
+	assert.Equal(t, 1, spans[0].DroppedAttributes)
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "second", spans[0].Events[0].Name)
+}
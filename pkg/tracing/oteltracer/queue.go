@@ -0,0 +1,336 @@
+package oteltracer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	queueFileExt              = ".pb"
+	queueTempFileExt          = ".tmp"
+	queueFileNameDigits       = 20
+	defaultQueueRetryInterval = 5 * time.Second
+)
+
+var ErrQueueDirRequired = errors.New("queue directory is required")
+
+// persistentClient is an otlptrace.Client that writes every upload to a directory before
+// sending it. A background goroutine sends the queued uploads in order through the wrapped
+// client and removes them once accepted. Uploads left on disk when the process stops are
+// sent after the next start.
+type persistentClient struct {
+	client otlptrace.Client
+	cfg    config.TraceExporterQueueConfig
+
+	mu      sync.Mutex
+	nextSeq uint64
+
+	notify   chan struct{}
+	stopCh   chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newPersistentClient(client otlptrace.Client, cfg *config.TraceExporterQueueConfig) *persistentClient {
+	return &persistentClient{
+		client: client,
+		cfg:    *cfg,
+		notify: make(chan struct{}, 1),
+		stopCh: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start starts the wrapped client and the goroutine sending the queued uploads,
+// starting with the ones left by a previous process.
+func (c *persistentClient) Start(ctx context.Context) error {
+	if c.cfg.Dir == "" {
+		return ErrQueueDirRequired
+	}
+	if err := os.MkdirAll(c.cfg.Dir, dirPermissions); err != nil {
+		return fmt.Errorf("failed to create queue directory: %w", err)
+	}
+	if err := c.removeTempFiles(); err != nil {
+		return err
+	}
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		c.nextSeq = entries[len(entries)-1].seq + 1
+	}
+
+	if err := c.client.Start(ctx); err != nil {
+		return err
+	}
+	go c.run()
+	c.signal()
+	return nil
+}
+
+// Stop waits for the in-flight upload and stops the wrapped client, even when ctx expires first.
+// The uploads still queued stay on disk. Only the first call stops the client, the next ones return nil.
+func (c *persistentClient) Stop(ctx context.Context) error {
+	var err error
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+		var waitErr error
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			waitErr = ctx.Err()
+		}
+		err = errors.Join(waitErr, c.client.Stop(ctx))
+	})
+	return err
+}
+
+// UploadTraces persists the spans to the queue directory. It returns once they are on disk.
+func (c *persistentClient) UploadTraces(_ context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	payload, err := proto.Marshal(&tracepb.TracesData{ResourceSpans: protoSpans})
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := filepath.Join(c.cfg.Dir, queueFileName(c.nextSeq))
+	if err := os.WriteFile(name+queueTempFileExt, payload, filePermissions); err != nil {
+		return fmt.Errorf("failed to queue spans: %w", err)
+	}
+	// the rename makes the upload visible to the sender only once it is completely written
+	if err := os.Rename(name+queueTempFileExt, name); err != nil {
+		return fmt.Errorf("failed to queue spans: %w", err)
+	}
+	c.nextSeq++
+
+	if err := c.enforceMaxSize(); err != nil {
+		otel.Handle(err)
+	}
+	c.signal()
+	return nil
+}
+
+func (c *persistentClient) signal() {
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *persistentClient) run() {
+	defer close(c.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		sent, err := c.sendOldest(ctx)
+		if err != nil {
+			otel.Handle(err)
+		}
+		if sent {
+			continue
+		}
+		if err != nil {
+			if !c.wait(c.retryInterval()) {
+				return
+			}
+			continue
+		}
+		select {
+		case <-c.notify:
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// sendOldest sends or discards the oldest queued upload. It returns false when the queue
+// is empty or the upload has to be retried later.
+func (c *persistentClient) sendOldest(ctx context.Context) (bool, error) {
+	entries, err := c.entries()
+	if err != nil || len(entries) == 0 {
+		return false, err
+	}
+	oldest := entries[0]
+
+	if c.cfg.MaxAge > 0 && time.Since(oldest.modTime) > c.cfg.MaxAge {
+		return true, c.remove(oldest, "expired")
+	}
+
+	payload, err := os.ReadFile(oldest.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// removed by enforceMaxSize in the meantime
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to read queued spans: %w", err)
+	}
+	data := &tracepb.TracesData{}
+	if err := proto.Unmarshal(payload, data); err != nil {
+		return true, errors.Join(fmt.Errorf("failed to decode queued spans: %w", err), c.remove(oldest, "corrupted"))
+	}
+	if err := c.client.UploadTraces(ctx, data.GetResourceSpans()); err != nil {
+		err = fmt.Errorf("failed to send queued spans: %w", err)
+		if isPermanentUploadError(err) {
+			return true, errors.Join(err, c.remove(oldest, "rejected"))
+		}
+		return false, err
+	}
+	if err := os.Remove(oldest.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return true, fmt.Errorf("failed to remove sent spans from queue: %w", err)
+	}
+	return true, nil
+}
+
+// permanentStatus matches the error of the OTLP/HTTP client for the responses with a status the OTLP
+// specification does not retry, e.g. 400 or 413. The client has no typed error for them.
+//
+//nolint:gochecknoglobals // compiled once
+var permanentStatus = regexp.MustCompile(`failed to send to \S+: [1-5]\d\d\b`)
+
+// isPermanentUploadError reports whether the collector rejected the upload for good, so that
+// sending it again fails the same way. The transport, timeout and retryable status errors, e.g.
+// 503, are retried. The partial successes are not errors, the upload is removed as sent.
+func isPermanentUploadError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return false
+	}
+	return permanentStatus.MatchString(err.Error())
+}
+
+// wait blocks for d, it returns false if the client was stopped in the meantime.
+func (c *persistentClient) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-c.stopCh:
+		return false
+	}
+}
+
+func (c *persistentClient) retryInterval() time.Duration {
+	return valueOrDefault(c.cfg.RetryInterval, defaultQueueRetryInterval)
+}
+
+// enforceMaxSize removes the oldest uploads until the queue fits in MaxSize.
+// Must be called with c.mu held.
+func (c *persistentClient) enforceMaxSize() error {
+	if c.cfg.MaxSize <= 0 {
+		return nil
+	}
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, e := range entries {
+		total += e.size
+	}
+	var errs []error
+	for _, e := range entries {
+		if total <= c.cfg.MaxSize {
+			break
+		}
+		errs = append(errs, c.remove(e, "queue full"))
+		total -= e.size
+	}
+	return errors.Join(errs...)
+}
+
+// remove discards a queued upload that will never be sent.
+func (c *persistentClient) remove(e queueEntry, reason string) error {
+	if err := os.Remove(e.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove queued spans: %w", err)
+	}
+	return fmt.Errorf("dropped queued spans %s: %s", filepath.Base(e.path), reason) //nolint:err113 // reported through otel.Handle
+}
+
+type queueEntry struct {
+	path    string
+	seq     uint64
+	size    int64
+	modTime time.Time
+}
+
+// entries lists the queued uploads, oldest first.
+func (c *persistentClient) entries() ([]queueEntry, error) {
+	dirEntries, err := os.ReadDir(c.cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue directory: %w", err)
+	}
+	entries := make([]queueEntry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		name := de.Name()
+		if de.IsDir() || !strings.HasSuffix(name, queueFileExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, queueFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			// removed since the directory was read
+			continue
+		}
+		entries = append(entries, queueEntry{
+			path:    filepath.Join(c.cfg.Dir, name),
+			seq:     seq,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+	return entries, nil
+}
+
+// removeTempFiles removes the uploads a previous process did not finish writing.
+func (c *persistentClient) removeTempFiles() error {
+	tempFiles, err := filepath.Glob(filepath.Join(c.cfg.Dir, "*"+queueFileExt+queueTempFileExt))
+	if err != nil {
+		return fmt.Errorf("failed to list queue directory: %w", err)
+	}
+	for _, f := range tempFiles {
+		if err := os.Remove(f); err != nil {
+			return fmt.Errorf("failed to remove incomplete queued spans: %w", err)
+		}
+	}
+	return nil
+}
+
+func queueFileName(seq uint64) string {
+	return fmt.Sprintf("%0*d%s", queueFileNameDigits, seq, queueFileExt)
+}
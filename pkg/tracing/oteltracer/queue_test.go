@@ -0,0 +1,159 @@
+package oteltracer_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collectortracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+// collector is an OTLP/HTTP stand-in recording the names of the received spans.
+type collector struct {
+	available atomic.Bool
+	// reject is the name of the spans rejected with 400 Bad Request
+	reject string
+	mu     sync.Mutex
+	spans  []string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !c.available.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	req := &collectortracepb.ExportTraceServiceRequest{}
+	if err := proto.Unmarshal(body, req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var names []string
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, s := range ss.GetSpans() {
+				if c.reject != "" && s.GetName() == c.reject {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				names = append(names, s.GetName())
+			}
+		}
+	}
+	c.mu.Lock()
+	c.spans = append(c.spans, names...)
+	c.mu.Unlock()
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *collector) received() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.spans...)
+}
+
+func TestNewTraceExporter_QueueSurvivesOutageAndRestart(t *testing.T) {
+	t.Parallel()
+	col := &collector{}
+	server := httptest.NewServer(col)
+	defer server.Close()
+
+	exporterCfg := config.TraceExporterConfig{
+		Type:        model.TraceExporterTypeHTTP,
+		Processor:   model.SpanProcessorTypeSimple,
+		EndpointURL: server.URL + "/v1/traces",
+		Queue: config.TraceExporterQueueConfig{
+			Enabled:       true,
+			Dir:           t.TempDir(),
+			RetryInterval: 10 * time.Millisecond,
+		},
+	}
+	newProvider := func() (func(name string), func()) {
+		exporter, err := oteltracer.NewTraceExporter(context.Background(), &config.TracingConfig{ExporterConfig: exporterCfg})
+		require.NoError(t, err)
+		tp := newTestProvider(t, exporterCfg, exporter)
+		end := func(name string) {
+			_, span := tp.Tracer("test").Start(context.Background(), name)
+			span.End()
+		}
+		return end, func() { require.NoError(t, tp.Shutdown(context.Background())) }
+	}
+
+	// the collector is down, the spans stay on disk across the restart
+	end, shutdown := newProvider()
+	end("first")
+	end("second")
+	shutdown()
+	assert.Empty(t, col.received())
+
+	col.available.Store(true)
+	end, shutdown = newProvider()
+	defer shutdown()
+	end("third")
+
+	assert.Eventually(t, func() bool { return len(col.received()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"first", "second", "third"}, col.received())
+}
+
+func TestNewTraceExporter_QueueDropsRejectedUploads(t *testing.T) {
+	t.Parallel()
+	col := &collector{reject: "invalid"}
+	col.available.Store(true)
+	server := httptest.NewServer(col)
+	defer server.Close()
+
+	dir := t.TempDir()
+	exporterCfg := config.TraceExporterConfig{
+		Type:        model.TraceExporterTypeHTTP,
+		Processor:   model.SpanProcessorTypeSimple,
+		EndpointURL: server.URL + "/v1/traces",
+		Queue:       config.TraceExporterQueueConfig{Enabled: true, Dir: dir, RetryInterval: time.Hour},
+	}
+	exporter, err := oteltracer.NewTraceExporter(context.Background(), &config.TracingConfig{ExporterConfig: exporterCfg})
+	require.NoError(t, err)
+	tp := newTestProvider(t, exporterCfg, exporter)
+
+	// the rejected upload is removed instead of blocking the queue until the next retry
+	for _, name := range []string{"invalid", "valid"} {
+		_, span := tp.Tracer("test").Start(context.Background(), name)
+		span.End()
+	}
+	assert.Eventually(t, func() bool { return len(col.received()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"valid"}, col.received())
+	assert.Eventually(t, func() bool {
+		entries, err := os.ReadDir(dir)
+		return err == nil && len(entries) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestNewTraceExporter_QueueStopTwice(t *testing.T) {
+	t.Parallel()
+	exporter, err := oteltracer.NewTraceExporter(context.Background(), &config.TracingConfig{
+		ExporterConfig: config.TraceExporterConfig{
+			Type:        model.TraceExporterTypeHTTP,
+			EndpointURL: "http://127.0.0.1:1/v1/traces",
+			Queue:       config.TraceExporterQueueConfig{Enabled: true, Dir: t.TempDir()},
+		},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// the wrapped client is stopped even though the context is done
+	assert.ErrorIs(t, exporter.Shutdown(ctx), context.Canceled)
+	assert.NotPanics(t, func() { _ = exporter.Shutdown(context.Background()) })
+}
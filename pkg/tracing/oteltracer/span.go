@@ -3,6 +3,7 @@ package oteltracer
 import (
 	"github.com/nash-567/goObserve/pkg/tracing/model"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -28,8 +29,14 @@ func (s *Span) IsRecording() bool {
 }
 
 // RecordError records an error in the span trace.
-func (s *Span) RecordError(err error) {
-	s.traceSpan.RecordError(err)
+func (s *Span) RecordError(err error, opts ...model.EventOption) {
+	eventConfig := model.NewEventConfig(opts...)
+	s.traceSpan.RecordError(err, toSDKEventConfig(&eventConfig)...)
+}
+
+// SetStatus sets the status of the span.
+func (s *Span) SetStatus(code model.StatusCode, description string) {
+	s.traceSpan.SetStatus(codes.Code(code), description)
 }
 
 // SetAttributes sets the attributes for the span.
@@ -44,6 +51,11 @@ func (s *Span) AddEvent(name string, opts ...model.EventOption) {
 	s.traceSpan.AddEvent(name, toSDKEventConfig(&eventConfig)...)
 }
 
+// SetName replaces the name the span was started with.
+func (s *Span) SetName(name string) {
+	s.traceSpan.SetName(name)
+}
+
 func toAttributes(attributes []model.KeyValue) []attribute.KeyValue {
 	attrs := make([]attribute.KeyValue, len(attributes))
 	for i, v := range attributes {
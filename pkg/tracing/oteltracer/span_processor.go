@@ -0,0 +1,113 @@
+package oteltracer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// compositeSpanProcessor runs the model.SpanProcessor of the provider in front of the
+// processors exporting the spans. A span dropped by one of them is not exported at all.
+type compositeSpanProcessor struct {
+	processors []model.SpanProcessor
+	exporters  []sdkTrace.SpanProcessor
+}
+
+func newCompositeSpanProcessor(processors []model.SpanProcessor, exporters []sdkTrace.SpanProcessor) *compositeSpanProcessor {
+	return &compositeSpanProcessor{processors: processors, exporters: exporters}
+}
+
+func (p *compositeSpanProcessor) OnStart(parent context.Context, s sdkTrace.ReadWriteSpan) {
+	if len(p.processors) > 0 {
+		span := newSpan(s)
+		for _, processor := range p.processors {
+			processor.OnStart(parent, span)
+		}
+	}
+	for _, exporter := range p.exporters {
+		exporter.OnStart(parent, s)
+	}
+}
+
+func (p *compositeSpanProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	if len(p.processors) > 0 {
+		span := readOnlySpan{span: s}
+		for _, processor := range p.processors {
+			if !processor.OnEnd(span) {
+				return
+			}
+		}
+	}
+	for _, exporter := range p.exporters {
+		exporter.OnEnd(s)
+	}
+}
+
+func (p *compositeSpanProcessor) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, exporter := range p.exporters {
+		errs = append(errs, exporter.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *compositeSpanProcessor) ForceFlush(ctx context.Context) error {
+	var errs []error
+	for _, exporter := range p.exporters {
+		errs = append(errs, exporter.ForceFlush(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// readOnlySpan implements model.ReadOnlySpan on top of the SDK span.
+type readOnlySpan struct {
+	span sdkTrace.ReadOnlySpan
+}
+
+func (s readOnlySpan) Name() string {
+	return s.span.Name()
+}
+
+func (s readOnlySpan) TraceID() string {
+	return s.span.SpanContext().TraceID().String()
+}
+
+func (s readOnlySpan) SpanID() string {
+	return s.span.SpanContext().SpanID().String()
+}
+
+func (s readOnlySpan) ParentSpanID() string {
+	if !s.span.Parent().HasSpanID() {
+		return ""
+	}
+	return s.span.Parent().SpanID().String()
+}
+
+func (s readOnlySpan) SpanKind() model.SpanKind {
+	return model.SpanKind(s.span.SpanKind())
+}
+
+func (s readOnlySpan) StartTime() time.Time {
+	return s.span.StartTime()
+}
+
+func (s readOnlySpan) EndTime() time.Time {
+	return s.span.EndTime()
+}
+
+func (s readOnlySpan) Attributes() []model.KeyValue {
+	attrs := s.span.Attributes()
+	kvs := make([]model.KeyValue, len(attrs))
+	for i, attr := range attrs {
+		kvs[i] = model.NewKeyValueFromAttribute(attr)
+	}
+	return kvs
+}
+
+func (s readOnlySpan) Status() (model.StatusCode, string) {
+	status := s.span.Status()
+	return model.StatusCode(status.Code), status.Description
+}
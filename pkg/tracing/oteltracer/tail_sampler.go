@@ -0,0 +1,342 @@
+package oteltracer
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultDecisionWait     = 10 * time.Second
+	defaultMaxTraces        = 10000
+	defaultMaxSpansPerTrace = 1000
+	maxDecisionTick         = time.Second
+)
+
+// ErrInvalidDecisionWait is returned by NewTraceProvider when TailSamplingConfig.DecisionWait is negative.
+var ErrInvalidDecisionWait = errors.New("tail sampling decision wait must not be negative")
+
+// TailSamplingStats holds the counters of the tail sampler created by NewTraceProvider.
+// It is safe for concurrent use, pass it to the provider using WithTailSamplingStats.
+type TailSamplingStats struct {
+	sampled      atomic.Uint64
+	notSampled   atomic.Uint64
+	evicted      atomic.Uint64
+	droppedSpans atomic.Uint64
+}
+
+// Sampled returns the number of traces exported.
+func (s *TailSamplingStats) Sampled() uint64 {
+	return s.sampled.Load()
+}
+
+// NotSampled returns the number of traces discarded because they matched no policy.
+func (s *TailSamplingStats) NotSampled() uint64 {
+	return s.notSampled.Load()
+}
+
+// Evicted returns the number of traces decided before the end of the decision window,
+// because TailSamplingConfig.MaxTraces was reached.
+func (s *TailSamplingStats) Evicted() uint64 {
+	return s.evicted.Load()
+}
+
+// DroppedSpans returns the number of spans discarded because their trace buffer was full.
+func (s *TailSamplingStats) DroppedSpans() uint64 {
+	return s.droppedSpans.Load()
+}
+
+// tailSampler buffers the ended spans per trace and forwards the sampled traces to the exporter
+// processors once the decision window of the trace has elapsed. The decisions are remembered
+// for another window, so the spans ending late follow the decision of their trace.
+type tailSampler struct {
+	cfg              config.TailSamplingConfig
+	decisionWait     time.Duration
+	maxTraces        int
+	maxSpansPerTrace int
+	stats            *TailSamplingStats
+	next             []sdkTrace.SpanProcessor
+
+	mu       sync.Mutex
+	pending  map[trace.TraceID]*list.Element
+	order    *list.List // of *traceBuffer, oldest first
+	decided  map[trace.TraceID]bool
+	decision *list.List // of decidedTrace, oldest first
+
+	stopCh   chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+type traceBuffer struct {
+	id      trace.TraceID
+	firstAt time.Time
+	spans   []sdkTrace.ReadOnlySpan
+}
+
+type decidedTrace struct {
+	id trace.TraceID
+	at time.Time
+}
+
+func validateTailSampling(cfg *config.TailSamplingConfig) error {
+	if cfg.DecisionWait < 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidDecisionWait, cfg.DecisionWait)
+	}
+	return nil
+}
+
+// newTailSampler expects a configuration checked by validateTailSampling.
+func newTailSampler(cfg *config.TailSamplingConfig, next []sdkTrace.SpanProcessor, stats *TailSamplingStats) *tailSampler {
+	s := &tailSampler{
+		cfg:              *cfg,
+		decisionWait:     valueOrDefault(cfg.DecisionWait, defaultDecisionWait),
+		maxTraces:        valueOrDefault(cfg.MaxTraces, defaultMaxTraces),
+		maxSpansPerTrace: valueOrDefault(cfg.MaxSpansPerTrace, defaultMaxSpansPerTrace),
+		stats:            stats,
+		next:             next,
+		pending:          map[trace.TraceID]*list.Element{},
+		order:            list.New(),
+		decided:          map[trace.TraceID]bool{},
+		decision:         list.New(),
+		stopCh:           make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+	// a few ticks per window, at least one for the windows shorter than 4ns
+	tick := min(s.decisionWait/4, maxDecisionTick)
+	if tick <= 0 {
+		tick = s.decisionWait
+	}
+	go s.run(tick)
+	return s
+}
+
+func (s *tailSampler) OnStart(parent context.Context, span sdkTrace.ReadWriteSpan) {
+	for _, p := range s.next {
+		p.OnStart(parent, span)
+	}
+}
+
+func (s *tailSampler) OnEnd(span sdkTrace.ReadOnlySpan) {
+	id := span.SpanContext().TraceID()
+
+	s.mu.Lock()
+	if sampled, ok := s.decided[id]; ok {
+		s.mu.Unlock()
+		if sampled {
+			s.forward([]sdkTrace.ReadOnlySpan{span})
+		}
+		return
+	}
+
+	var ready [][]sdkTrace.ReadOnlySpan
+	if el, ok := s.pending[id]; ok {
+		buf := el.Value.(*traceBuffer) //nolint:forcetypeassert // the list only holds trace buffers
+		if len(buf.spans) >= s.maxSpansPerTrace {
+			s.stats.droppedSpans.Add(1)
+		} else {
+			buf.spans = append(buf.spans, span)
+		}
+	} else {
+		for s.order.Len() >= s.maxTraces {
+			s.stats.evicted.Add(1)
+			ready = s.decideLocked(s.order.Front(), time.Now(), ready)
+		}
+		s.pending[id] = s.order.PushBack(&traceBuffer{id: id, firstAt: time.Now(), spans: []sdkTrace.ReadOnlySpan{span}})
+	}
+	s.mu.Unlock()
+
+	for _, spans := range ready {
+		s.forward(spans)
+	}
+}
+
+func (s *tailSampler) run(tick time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case now := <-ticker.C:
+			s.decideExpired(now)
+		}
+	}
+}
+
+// decideExpired decides the traces whose decision window has elapsed and forgets the old decisions.
+func (s *tailSampler) decideExpired(now time.Time) {
+	s.mu.Lock()
+	var ready [][]sdkTrace.ReadOnlySpan
+	for el := s.order.Front(); el != nil; el = s.order.Front() {
+		buf := el.Value.(*traceBuffer) //nolint:forcetypeassert // the list only holds trace buffers
+		if now.Sub(buf.firstAt) < s.decisionWait {
+			break
+		}
+		ready = s.decideLocked(el, now, ready)
+	}
+	for el := s.decision.Front(); el != nil; el = s.decision.Front() {
+		d := el.Value.(decidedTrace) //nolint:forcetypeassert // the list only holds decisions
+		if now.Sub(d.at) < s.decisionWait {
+			break
+		}
+		delete(s.decided, d.id)
+		s.decision.Remove(el)
+	}
+	s.mu.Unlock()
+
+	for _, spans := range ready {
+		s.forward(spans)
+	}
+}
+
+// decideAll decides every buffered trace, without waiting for the end of the decision windows.
+func (s *tailSampler) decideAll() {
+	s.mu.Lock()
+	var ready [][]sdkTrace.ReadOnlySpan
+	now := time.Now()
+	for el := s.order.Front(); el != nil; el = s.order.Front() {
+		ready = s.decideLocked(el, now, ready)
+	}
+	s.mu.Unlock()
+
+	for _, spans := range ready {
+		s.forward(spans)
+	}
+}
+
+// decideLocked removes the trace of el from the buffer and records the decision.
+// The spans of a sampled trace are appended to ready. s.mu must be held.
+func (s *tailSampler) decideLocked(el *list.Element, now time.Time, ready [][]sdkTrace.ReadOnlySpan) [][]sdkTrace.ReadOnlySpan {
+	buf := el.Value.(*traceBuffer) //nolint:forcetypeassert // the list only holds trace buffers
+	s.order.Remove(el)
+	delete(s.pending, buf.id)
+
+	sampled := s.sample(buf)
+	if sampled {
+		s.stats.sampled.Add(1)
+		ready = append(ready, buf.spans)
+	} else {
+		s.stats.notSampled.Add(1)
+	}
+
+	if s.decision.Len() >= s.maxTraces {
+		oldest := s.decision.Front()
+		delete(s.decided, oldest.Value.(decidedTrace).id) //nolint:forcetypeassert // the list only holds decisions
+		s.decision.Remove(oldest)
+	}
+	s.decided[buf.id] = sampled
+	s.decision.PushBack(decidedTrace{id: buf.id, at: now})
+	return ready
+}
+
+// sample reports whether the trace matches any of the policies.
+func (s *tailSampler) sample(buf *traceBuffer) bool {
+	var (
+		root       sdkTrace.ReadOnlySpan
+		start, end time.Time
+	)
+	for _, span := range buf.spans {
+		if s.cfg.Errors && span.Status().Code == codes.Error {
+			return true
+		}
+		if s.matchAttributes(span) {
+			return true
+		}
+		if parent := span.Parent(); !parent.IsValid() || parent.IsRemote() {
+			root = span
+		}
+		if start.IsZero() || span.StartTime().Before(start) {
+			start = span.StartTime()
+		}
+		if span.EndTime().After(end) {
+			end = span.EndTime()
+		}
+	}
+
+	if s.cfg.MinDuration > 0 {
+		// without the root span, the time range of the buffered spans is the best estimate
+		duration := end.Sub(start)
+		if root != nil {
+			duration = root.EndTime().Sub(root.StartTime())
+		}
+		if duration > s.cfg.MinDuration {
+			return true
+		}
+	}
+	return sampleRatio(buf.id, s.cfg.Ratio)
+}
+
+func (s *tailSampler) matchAttributes(span sdkTrace.ReadOnlySpan) bool {
+	if len(s.cfg.Attributes) == 0 {
+		return false
+	}
+	for _, attr := range span.Attributes() {
+		for _, policy := range s.cfg.Attributes {
+			if string(attr.Key) != policy.Key {
+				continue
+			}
+			value := attr.Value.Emit()
+			for _, v := range policy.Values {
+				if v == value {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// sampleRatio samples the ratio of the trace IDs like the SDK trace ID ratio sampler, so the
+// services sampling with the same ratio keep the same traces.
+func sampleRatio(id trace.TraceID, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	bound := uint64(ratio * (1 << 63))
+	return binary.BigEndian.Uint64(id[8:16])>>1 < bound
+}
+
+func (s *tailSampler) forward(spans []sdkTrace.ReadOnlySpan) {
+	for _, span := range spans {
+		for _, p := range s.next {
+			p.OnEnd(span)
+		}
+	}
+}
+
+// ForceFlush decides all the buffered traces, then flushes the exporter processors.
+func (s *tailSampler) ForceFlush(ctx context.Context) error {
+	s.decideAll()
+	var errs []error
+	for _, p := range s.next {
+		errs = append(errs, p.ForceFlush(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown decides all the buffered traces, then shuts the exporter processors down.
+func (s *tailSampler) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+	<-s.done
+	s.decideAll()
+	var errs []error
+	for _, p := range s.next {
+		errs = append(errs, p.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
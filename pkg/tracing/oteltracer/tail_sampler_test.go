@@ -0,0 +1,190 @@
+package oteltracer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+func newTailSamplingProvider(
+	t *testing.T,
+	tailCfg config.TailSamplingConfig,
+	exporter sdkTrace.SpanExporter,
+	stats *oteltracer.TailSamplingStats,
+) *sdkTrace.TracerProvider {
+	t.Helper()
+	tailCfg.Enabled = true
+	if tailCfg.DecisionWait == 0 {
+		// decisions are taken by ForceFlush
+		tailCfg.DecisionWait = time.Hour
+	}
+	tp, err := oteltracer.NewTraceProvider(&config.TracingConfig{
+		Enabled:        true,
+		ExporterConfig: config.TraceExporterConfig{Processor: model.SpanProcessorTypeSimple},
+		TailSampling:   tailCfg,
+	}, exporter, "test", oteltracer.WithTailSamplingStats(stats))
+	require.NoError(t, err)
+	sdkTP, ok := tp.(*sdkTrace.TracerProvider)
+	require.True(t, ok)
+	t.Cleanup(func() { _ = sdkTP.Shutdown(context.Background()) })
+	return sdkTP
+}
+
+// startTrace ends a child span then returns its root span, still running.
+func startTrace(tp trace.TracerProvider, name string, opts ...trace.SpanStartOption) trace.Span {
+	ctx, root := tp.Tracer("test").Start(context.Background(), name, opts...)
+	_, child := tp.Tracer("test").Start(ctx, name+"-child")
+	child.End()
+	return root
+}
+
+func exportedNames(exporter *tracetest.InMemoryExporter) []string {
+	var names []string
+	for _, s := range exporter.GetSpans() {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestTailSampler_Errors(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	stats := &oteltracer.TailSamplingStats{}
+	tp := newTailSamplingProvider(t, config.TailSamplingConfig{Errors: true}, exporter, stats)
+
+	failed := startTrace(tp, "failed")
+	failed.SetStatus(codes.Error, "boom")
+	failed.End()
+	startTrace(tp, "ok").End()
+	assert.Empty(t, exporter.GetSpans(), "spans are buffered until the decision")
+
+	require.NoError(t, tp.ForceFlush(context.Background()))
+	assert.Equal(t, []string{"failed-child", "failed"}, exportedNames(exporter))
+	assert.Equal(t, uint64(1), stats.Sampled())
+	assert.Equal(t, uint64(1), stats.NotSampled())
+}
+
+func TestTailSampler_MinDuration(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	stats := &oteltracer.TailSamplingStats{}
+	tp := newTailSamplingProvider(t, config.TailSamplingConfig{MinDuration: time.Second}, exporter, stats)
+
+	start := time.Now()
+	startTrace(tp, "slow", trace.WithTimestamp(start.Add(-2*time.Second))).End(trace.WithTimestamp(start))
+	startTrace(tp, "fast", trace.WithTimestamp(start.Add(-time.Millisecond))).End(trace.WithTimestamp(start))
+
+	require.NoError(t, tp.ForceFlush(context.Background()))
+	assert.Equal(t, []string{"slow-child", "slow"}, exportedNames(exporter))
+}
+
+func TestTailSampler_Attributes(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	stats := &oteltracer.TailSamplingStats{}
+	tp := newTailSamplingProvider(t, config.TailSamplingConfig{
+		Attributes: []config.TailSamplingAttributeConfig{{Key: "tenant.id", Values: []string{"acme", "globex"}}},
+	}, exporter, stats)
+
+	startTrace(tp, "acme", trace.WithAttributes(attribute.String("tenant.id", "acme"))).End()
+	startTrace(tp, "initech", trace.WithAttributes(attribute.String("tenant.id", "initech"))).End()
+
+	require.NoError(t, tp.ForceFlush(context.Background()))
+	assert.Equal(t, []string{"acme-child", "acme"}, exportedNames(exporter))
+}
+
+func TestTailSampler_Ratio(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	stats := &oteltracer.TailSamplingStats{}
+	tp := newTailSamplingProvider(t, config.TailSamplingConfig{Ratio: 0.5}, exporter, stats)
+
+	const traces = 1000
+	for i := 0; i < traces; i++ {
+		startTrace(tp, "trace").End()
+	}
+
+	require.NoError(t, tp.ForceFlush(context.Background()))
+	assert.Equal(t, uint64(traces), stats.Sampled()+stats.NotSampled())
+	assert.InDelta(t, traces/2, stats.Sampled(), traces/10)
+	assert.Len(t, exporter.GetSpans(), 2*int(stats.Sampled()))
+}
+
+func TestTailSampler_MemoryBounds(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	stats := &oteltracer.TailSamplingStats{}
+	tp := newTailSamplingProvider(t, config.TailSamplingConfig{
+		Errors:           true,
+		MaxTraces:        1,
+		MaxSpansPerTrace: 1,
+	}, exporter, stats)
+
+	first := startTrace(tp, "first")
+	first.SetStatus(codes.Error, "boom")
+	// the root span does not fit in the trace buffer, so the trace has no error span
+	first.End()
+	// the first trace is evicted to make room for the second one
+	startTrace(tp, "second").End()
+
+	assert.Equal(t, uint64(1), stats.Evicted())
+	assert.Equal(t, uint64(2), stats.DroppedSpans())
+	require.NoError(t, tp.ForceFlush(context.Background()))
+	assert.Equal(t, uint64(2), stats.NotSampled())
+	assert.Empty(t, exporter.GetSpans())
+}
+
+func TestTailSampler_DecisionWait(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	stats := &oteltracer.TailSamplingStats{}
+	tp := newTailSamplingProvider(t, config.TailSamplingConfig{
+		Errors:       true,
+		DecisionWait: 100 * time.Millisecond,
+	}, exporter, stats)
+
+	ctx, root := tp.Tracer("test").Start(context.Background(), "root")
+	_, child := tp.Tracer("test").Start(ctx, "child")
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+
+	assert.Eventually(t, func() bool { return len(exporter.GetSpans()) == 1 }, time.Second, 5*time.Millisecond)
+
+	// the trace was sampled, the spans ending late are exported right away
+	root.End()
+	assert.Equal(t, []string{"child", "root"}, exportedNames(exporter))
+}
+
+func TestTailSampler_InvalidDecisionWait(t *testing.T) {
+	t.Parallel()
+	_, err := oteltracer.NewTraceProvider(&config.TracingConfig{
+		Enabled:      true,
+		TailSampling: config.TailSamplingConfig{Enabled: true, DecisionWait: -time.Second},
+	}, tracetest.NewInMemoryExporter(), "test")
+	require.ErrorIs(t, err, oteltracer.ErrInvalidDecisionWait)
+}
+
+func TestTailSampler_ShortDecisionWait(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	// shorter than the 4 ticks of a window
+	tp := newTailSamplingProvider(t, config.TailSamplingConfig{Errors: true, DecisionWait: time.Nanosecond},
+		exporter, &oteltracer.TailSamplingStats{})
+
+	_, span := tp.Tracer("test").Start(context.Background(), "root")
+	span.SetStatus(codes.Error, "boom")
+	span.End()
+	assert.Eventually(t, func() bool { return len(exporter.GetSpans()) == 1 }, time.Second, 5*time.Millisecond)
+}
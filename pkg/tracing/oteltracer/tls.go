@@ -0,0 +1,19 @@
+package oteltracer
+
+import (
+	"crypto/tls"
+
+	"github.com/nash-567/goObserve/internal/tlsconfig"
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+)
+
+var (
+	ErrInvalidCACertificate = tlsconfig.ErrInvalidCACertificate
+	ErrIncompleteClientCert = tlsconfig.ErrIncompleteClientCert
+)
+
+// newTLSConfig builds the tls.Config used by the OTLP and zipkin exporters, shared with the metric exporters.
+// It returns nil when no TLS option is set, so the exporter falls back to the system defaults.
+func newTLSConfig(cfg *config.TraceExporterTLSConfig) (*tls.Config, error) {
+	return tlsconfig.New(cfg)
+}
@@ -58,6 +58,9 @@ func toSDKSpanStartConfig(cfg *model.SpanConfig) []trace.SpanStartOption {
 	if !cfg.Timestamp().IsZero() {
 		opts = append(opts, trace.WithTimestamp(cfg.Timestamp()))
 	}
+	if cfg.SpanKind() != model.SpanKindUnspecified {
+		opts = append(opts, trace.WithSpanKind(trace.SpanKind(cfg.SpanKind())))
+	}
 	return opts
 }
 
@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/nash-567/goObserve/pkg/logger"
+	logModel "github.com/nash-567/goObserve/pkg/logger/model"
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+)
+
+// Recover handles a panic of the calling function, it must be deferred directly:
+//
+//	ctx, span := tracer.StartSpan(ctx, "job")
+//	defer span.End()
+//	defer tracing.Recover(ctx, span, false)
+//
+// The panic value and stack trace are recorded on the span as an exception event, the span gets
+// an error status and the panic is logged at ERROR level through the logger of ctx. The span is
+// not ended, the deferred span.End runs after Recover. When repanic is set the panic continues
+// afterwards, otherwise the calling function returns its current result values. Recover does
+// nothing when there is no panic.
+func Recover(ctx context.Context, span model.Span, repanic bool) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	handlePanic(ctx, span, r)
+	if repanic {
+		panic(r)
+	}
+}
+
+func handlePanic(ctx context.Context, span model.Span, recovered any) {
+	RecordPanic(span, recovered)
+	logger.FromContext(ctx).WithFields(logModel.Fields{
+		"panic": fmt.Sprint(recovered),
+		"stack": string(debug.Stack()),
+	}).Error("recovered from panic")
+}
+
+// RecoveryOption configures the middleware created by RecoveryMiddleware.
+type RecoveryOption func(*recoveryConfig)
+
+type recoveryConfig struct {
+	repanic bool
+}
+
+// WithRepanic makes the middleware continue the panic after handling it, e.g. to let an outer
+// middleware or the http.Server handle it. By default the panic is converted to a 500 response.
+func WithRepanic(repanic bool) RecoveryOption {
+	return func(c *recoveryConfig) {
+		c.repanic = repanic
+	}
+}
+
+// RecoveryMiddleware recovers the panics of the next handler and records them on the span of the
+// request, found with tracer.SpanFromContext, e.g. the one started by httpobserve or otelhttp before
+// it. That span is left to be ended by its middleware. A span is only started, and ended, by
+// RecoveryMiddleware when the request context has no recording span. A panic is handled like Recover
+// does, then converted to a 500 Internal Server Error response unless WithRepanic is set.
+// http.ErrAbortHandler is always re-panicked, since it is used to abort the response on purpose.
+func RecoveryMiddleware(tracer model.Tracer, opts ...RecoveryOption) func(http.Handler) http.Handler {
+	var cfg recoveryConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			span := tracer.SpanFromContext(ctx)
+			if !span.IsRecording() {
+				ctx, span = tracer.StartSpan(ctx, "HTTP "+r.Method,
+					model.WithSpanKind(model.SpanKindServer),
+					model.WithAttributes(
+						model.NewKeyValue("http.request.method", r.Method),
+						model.NewKeyValue("url.path", r.URL.Path),
+					),
+				)
+				defer span.End()
+				r = r.WithContext(ctx)
+			}
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(recovered)
+				}
+				handlePanic(ctx, span, recovered)
+				if cfg.repanic {
+					panic(recovered)
+				}
+				span.SetAttributes(model.NewKeyValue("http.response.status_code", int64(http.StatusInternalServerError)))
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
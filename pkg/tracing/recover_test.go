@@ -0,0 +1,125 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/nash-567/goObserve/pkg/logger"
+	"github.com/nash-567/goObserve/pkg/logger/config"
+	"github.com/nash-567/goObserve/pkg/tracing"
+)
+
+func makeTestLoggerContext() (context.Context, *strings.Builder) {
+	output := new(strings.Builder)
+	log := logger.NewSlogLogger(&config.Config{Output: output, Level: "INFO"})
+	return logger.NewContextWithLogger(context.Background(), log), output
+}
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+	tracer, exporter := makeTestTracer()
+	ctx, output := makeTestLoggerContext()
+
+	job := func() (err error) {
+		ctx, span := tracer.StartSpan(ctx, "job")
+		defer span.End()
+		defer tracing.Recover(ctx, span, false)
+		panic("boom")
+	}
+	require.NotPanics(t, func() { _ = job() })
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "exception", spans[0].Events[0].Name)
+
+	outputMustContain(t, output.String(), `"level":"ERROR"`, `"msg":"recovered from panic"`, `"panic":"boom"`, `"stack":"goroutine`)
+}
+
+func TestRecover_Repanic(t *testing.T) {
+	t.Parallel()
+	tracer, exporter := makeTestTracer()
+	ctx, _ := makeTestLoggerContext()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_, span := tracer.StartSpan(ctx, "job")
+		defer span.End()
+		defer tracing.Recover(ctx, span, true)
+		panic("boom")
+	})
+	require.Len(t, exporter.GetSpans(), 1)
+	assert.Equal(t, codes.Error, exporter.GetSpans()[0].Status.Code)
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	t.Parallel()
+	tracer, exporter := makeTestTracer()
+	ctx, output := makeTestLoggerContext()
+
+	handler := tracing.RecoveryMiddleware(tracer)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil).WithContext(ctx))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "HTTP GET", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	outputMustContain(t, output.String(), `"msg":"recovered from panic"`)
+}
+
+func TestRecoveryMiddleware_AbortHandler(t *testing.T) {
+	t.Parallel()
+	tracer, _ := makeTestTracer()
+
+	handler := tracing.RecoveryMiddleware(tracer)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func outputMustContain(t *testing.T, output string, substrings ...string) {
+	t.Helper()
+	for _, s := range substrings {
+		if !strings.Contains(output, s) {
+			t.Fatalf("output does not contain %s\n\toutput: %v", s, output)
+		}
+	}
+}
+
+func TestRecoveryMiddleware_RequestSpan(t *testing.T) {
+	t.Parallel()
+	tracer, exporter := makeTestTracer()
+	ctx, _ := makeTestLoggerContext()
+
+	handler := tracing.RecoveryMiddleware(tracer)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+	// the span of a tracing middleware running before, started without goObserve, e.g. by otelhttp:
+	// it records the panic and is ended by its owner
+	ctx, span := tracer.TracerProvider().Tracer("otelhttp").Start(ctx, "GET /orders")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil).WithContext(ctx))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, exporter.GetSpans())
+	span.End()
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /orders", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "exception", spans[0].Events[0].Name)
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nash-567/goObserve/pkg/tracing/model"
+)
+
+// Run starts a span named spanName, calls fn with the context containing the span and ends the span.
+//
+// An error returned by fn is recorded on the span, which then ends with an error status.
+// A panic in fn is recorded on the span as an exception event with the stack trace, the
+// span ends with an error status and the panic continues.
+func Run(
+	ctx context.Context,
+	tracer model.Tracer,
+	spanName string,
+	fn func(ctx context.Context) error,
+	opts ...model.SpanStartOption,
+) error {
+	_, err := Do(ctx, tracer, spanName, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts...)
+	return err
+}
+
+// Do is the same as Run for functions returning a value alongside the error.
+func Do[T any](
+	ctx context.Context,
+	tracer model.Tracer,
+	spanName string,
+	fn func(ctx context.Context) (T, error),
+	opts ...model.SpanStartOption,
+) (result T, err error) {
+	ctx, span := tracer.StartSpan(ctx, spanName, opts...)
+	defer func() {
+		if r := recover(); r != nil {
+			RecordPanic(span, r)
+			span.End()
+			panic(r)
+		}
+		RecordResult(span, err)
+		span.End()
+	}()
+
+	return fn(ctx)
+}
+
+// RecordResult records err on the span and sets the error status. It does nothing if err is nil.
+func RecordResult(span model.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(model.StatusCodeError, err.Error())
+}
+
+// RecordPanic records the recovered panic value as an exception event with the stack trace
+// of the panicking goroutine and sets the error status. It must be called from the deferred
+// function that recovered the panic, otherwise the stack trace does not include the panic.
+func RecordPanic(span model.Span, recovered any) {
+	err := panicError(recovered)
+	span.RecordError(err, model.WithStackTrace(true))
+	span.SetStatus(model.StatusCodeError, err.Error())
+}
+
+// panicError converts a recovered panic value to an error, wrapping it when it already is one.
+func panicError(recovered any) error {
+	if err, ok := recovered.(error); ok {
+		return fmt.Errorf("panic: %w", err)
+	}
+	return fmt.Errorf("panic: %v", recovered) //nolint:err113 // the panic value is only known at runtime
+}
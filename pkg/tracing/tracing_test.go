@@ -0,0 +1,92 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/nash-567/goObserve/pkg/tracing"
+	"github.com/nash-567/goObserve/pkg/tracing/config"
+	"github.com/nash-567/goObserve/pkg/tracing/oteltracer"
+)
+
+var errTest = errors.New("test error")
+
+func makeTestTracer() (*oteltracer.Tracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdkTrace.NewTracerProvider(sdkTrace.WithSyncer(exporter))
+	return oteltracer.NewTracer(&config.TracingConfig{}, tp), exporter
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	tracer, exporter := makeTestTracer()
+
+	err := tracing.Run(context.Background(), tracer, "ok", func(ctx context.Context) error {
+		assert.True(t, tracer.SpanFromContext(ctx).IsRecording())
+		return nil
+	})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "ok", spans[0].Name)
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	assert.Empty(t, spans[0].Events)
+}
+
+func TestRun_Error(t *testing.T) {
+	t.Parallel()
+	tracer, exporter := makeTestTracer()
+
+	err := tracing.Run(context.Background(), tracer, "failed", func(context.Context) error {
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, sdkTrace.Status{Code: codes.Error, Description: errTest.Error()}, spans[0].Status)
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "exception", spans[0].Events[0].Name)
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+	tracer, exporter := makeTestTracer()
+
+	got, err := tracing.Do(context.Background(), tracer, "lookup", func(context.Context) (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, got)
+	assert.Len(t, exporter.GetSpans(), 1)
+}
+
+func TestDo_Panic(t *testing.T) {
+	t.Parallel()
+	tracer, exporter := makeTestTracer()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_, _ = tracing.Do(context.Background(), tracer, "panics", func(context.Context) (int, error) {
+			panic("boom")
+		})
+	})
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, sdkTrace.Status{Code: codes.Error, Description: "panic: boom"}, spans[0].Status)
+	require.Len(t, spans[0].Events, 1)
+	attrs := map[string]string{}
+	for _, kv := range spans[0].Events[0].Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, "panic: boom", attrs["exception.message"])
+	assert.Contains(t, attrs["exception.stacktrace"], "TestDo_Panic")
+}